@@ -0,0 +1,54 @@
+// Package blob implements storage for uploaded files.
+package blob
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNotExists is returned when a blob doesn't exist.
+var ErrNotExists = errors.New("blob does not exist")
+
+// ErrInvalidKey is returned for keys that cannot be stored.
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store stores blobs by key.
+//
+// Keys are slash separated paths, the extension of the last
+// element determines the content type when serving.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidKey checks whether key is safe to use as a relative path.
+func ValidKey(key string) bool {
+	if key == "" || !utf8.ValidString(key) {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	for _, r := range key {
+		if r == '\\' || r == ':' || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentType returns the content type of key based on its extension.
+func ContentType(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,125 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "games/a/cover.jpg", []byte("cover")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.Get(ctx, "games/a/cover.jpg")
+	if err != nil || string(data) != "cover" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	if _, err := store.Get(ctx, "games/a/missing.jpg"); err != ErrNotExists {
+		t.Errorf("got %v, expected ErrNotExists", err)
+	}
+	if err := store.Delete(ctx, "games/a/cover.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "games/a/cover.jpg"); err != ErrNotExists {
+		t.Errorf("got %v after delete, expected ErrNotExists", err)
+	}
+	if err := store.Delete(ctx, "games/a/cover.jpg"); err != nil {
+		t.Errorf("deleting missing blob: %v", err)
+	}
+	for _, key := range []string{"../escape.jpg", "/root.jpg", "games//cover.jpg", `games\cover.jpg`} {
+		if err := store.Put(ctx, key, nil); err != ErrInvalidKey {
+			t.Errorf("%q: got %v, expected ErrInvalidKey", key, err)
+		}
+	}
+	if err := store.Put(ctx, "builds/My Game/index.html", []byte("game")); err != nil {
+		t.Errorf("key with space: %v", err)
+	}
+}
+
+func TestDir(t *testing.T) {
+	testStore(t, &Dir{Root: t.TempDir()})
+}
+
+func TestBucket(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		name, ok := strings.CutPrefix(r.URL.Path, "/bucket/")
+		if !ok {
+			http.Error(w, "wrong bucket", http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("Content-Type") != ContentType(name) {
+				http.Error(w, "wrong content type", http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			objects[name] = data
+		case http.MethodGet:
+			data, ok := objects[name]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = io.Copy(w, bytes.NewReader(data))
+		case http.MethodDelete:
+			if _, ok := objects[name]; !ok {
+				http.NotFound(w, r)
+				return
+			}
+			delete(objects, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	testStore(t, &Bucket{Name: "bucket", Client: server.Client(), Endpoint: server.URL})
+}
+
+func TestServerPublic(t *testing.T) {
+	ctx := context.Background()
+	store := &Dir{Root: t.TempDir()}
+	for _, key := range []string{"games/a/cover.jpg", "builds/a/1/index.html"} {
+		if err := store.Put(ctx, key, []byte("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	router := http.NewServeMux()
+	(&Server{Store: store, Public: func(key string) bool {
+		return strings.HasPrefix(key, "games/")
+	}}).Register(router)
+
+	for key, status := range map[string]int{
+		"games/a/cover.jpg":     http.StatusOK,
+		"games/a/missing.jpg":   http.StatusNotFound,
+		"builds/a/1/index.html": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/blob/"+key, nil))
+		if w.Code != status {
+			t.Errorf("%v: got status %v, expected %v", key, w.Code, status)
+		}
+	}
+
+	router = http.NewServeMux()
+	(&Server{Store: store}).Register(router)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/blob/games/a/cover.jpg", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("without Public: got status %v, expected %v", w.Code, http.StatusNotFound)
+	}
+}
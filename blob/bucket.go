@@ -0,0 +1,118 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+// DefaultBucketEndpoint is the Cloud Storage XML API endpoint.
+const DefaultBucketEndpoint = "https://storage.googleapis.com"
+
+// Bucket stores blobs in a cloud storage bucket using the XML API.
+//
+// Any service compatible with the Cloud Storage XML API can be used
+// by changing Endpoint.
+type Bucket struct {
+	Name string
+	// Client is used for requests and must add authentication.
+	Client *http.Client
+	// Endpoint defaults to DefaultBucketEndpoint.
+	Endpoint string
+}
+
+// NewBucket creates a Bucket using application default credentials.
+func NewBucket(ctx context.Context, name string) (*Bucket, error) {
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/devstorage.read_write")
+	if err != nil {
+		return nil, err
+	}
+	return &Bucket{Name: name, Client: client}, nil
+}
+
+// url returns the object location of key.
+func (bucket *Bucket) url(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	endpoint := bucket.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultBucketEndpoint
+	}
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.TrimSuffix(endpoint, "/") + "/" + url.PathEscape(bucket.Name) + "/" + strings.Join(parts, "/"), nil
+}
+
+// do sends a request for key and returns the response body.
+func (bucket *Bucket) do(ctx context.Context, method, key string, body []byte) ([]byte, error) {
+	location, err := bucket.url(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, location, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", ContentType(key))
+	}
+
+	client := bucket.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return nil, ErrNotExists
+	case response.StatusCode >= 300:
+		return nil, fmt.Errorf("%v %v: %v", method, key, response.Status)
+	}
+	return data, nil
+}
+
+// Put uploads data to key, replacing the previous content.
+func (bucket *Bucket) Put(ctx context.Context, key string, data []byte) error {
+	if data == nil {
+		data = []byte{}
+	}
+	_, err := bucket.do(ctx, http.MethodPut, key, data)
+	return err
+}
+
+// Get downloads the content of key.
+func (bucket *Bucket) Get(ctx context.Context, key string) ([]byte, error) {
+	return bucket.do(ctx, http.MethodGet, key, nil)
+}
+
+// Delete removes key, deleting a missing key is not an error.
+func (bucket *Bucket) Delete(ctx context.Context, key string) error {
+	_, err := bucket.do(ctx, http.MethodDelete, key, nil)
+	if err == ErrNotExists {
+		return nil
+	}
+	return err
+}
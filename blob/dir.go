@@ -0,0 +1,74 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Dir stores blobs in a local directory.
+type Dir struct {
+	Root string
+}
+
+// path returns the file location of key.
+func (dir *Dir) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(dir.Root, filepath.FromSlash(key)), nil
+}
+
+// Put writes data to key, replacing the previous content.
+func (dir *Dir) Put(ctx context.Context, key string, data []byte) error {
+	path, err := dir.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// Get reads the content of key.
+func (dir *Dir) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := dir.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotExists
+	}
+	return data, err
+}
+
+// Delete removes key, deleting a missing key is not an error.
+func (dir *Dir) Delete(ctx context.Context, key string) error {
+	path, err := dir.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
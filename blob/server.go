@@ -0,0 +1,50 @@
+package blob
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Server serves blobs from a Store.
+type Server struct {
+	Log   *slog.Logger
+	Store Store
+	// Public reports whether key may be served, when nil no blobs are served.
+	//
+	// The store can contain untrusted content, which must not be served
+	// from the site origin.
+	Public func(key string) bool
+}
+
+// Register registers blob endpoints to router.
+func (server *Server) Register(router *http.ServeMux) {
+	router.HandleFunc("/blob/{key...}", server.Serve)
+}
+
+// Serve responds with the content of the requested blob.
+//
+// Keys are never reused, hence responses can be cached indefinitely.
+func (server *Server) Serve(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !ValidKey(key) || server.Public == nil || !server.Public(key) {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := server.Store.Get(r.Context(), key)
+	if err == ErrNotExists {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		server.Log.Error("failed to get blob", "key", key, "error", err)
+		http.Error(w, "Unable to get file.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType(key))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	_, _ = w.Write(data)
+}
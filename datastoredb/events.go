@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"cloud.google.com/go/datastore"
 
@@ -35,6 +36,26 @@ func newTeamKey(eventkey *datastore.Key, teamid event.TeamID) *datastore.Key {
 	return datastore.IDKey("Team", int64(teamid), eventkey)
 }
 
+// newModerationKey returns moderation key associated with event, ballot ref and aspect.
+func newModerationKey(eventkey *datastore.Key, ref, aspect string) *datastore.Key {
+	return datastore.NameKey("Moderation", ref+"-"+aspect, eventkey)
+}
+
+// newFeedbackKey returns feedback key associated with event, ballot ref and aspect.
+func newFeedbackKey(eventkey *datastore.Key, ref, aspect string) *datastore.Key {
+	return datastore.NameKey("Feedback", ref+"-"+aspect, eventkey)
+}
+
+// newSeekerKey returns looking-for-team card key associated with event and user.
+func newSeekerKey(eventkey *datastore.Key, userid user.UserID) *datastore.Key {
+	return datastore.IDKey("Seeker", int64(userid), eventkey)
+}
+
+// newLinkStatusKey returns link status key associated with event and team.
+func newLinkStatusKey(eventkey *datastore.Key, teamid event.TeamID) *datastore.Key {
+	return datastore.IDKey("LinkStatus", int64(teamid), eventkey)
+}
+
 // newBallotKey returns event key associated with event, voter and team.
 func newBallotKey(eventkey *datastore.Key, voter user.UserID, votingFor event.TeamID) *datastore.Key {
 	id := fmt.Sprintf("%v-%v", voter, votingFor)
@@ -323,7 +344,7 @@ func (repo *Events) Teams(eventid event.EventID) ([]*event.Team, error) {
 }
 
 // CreateIncompleteBallots creates new incomplete ballots for a user.
-func (repo *Events) CreateIncompleteBallots(eventid event.EventID, userid user.UserID) (complete, incomplete []*event.BallotInfo, err error) {
+func (repo *Events) CreateIncompleteBallots(eventid event.EventID, userid user.UserID, eligible func(*event.Team) bool) (complete, incomplete []*event.BallotInfo, err error) {
 	const FirstBatchCount = 3
 
 	//TODO: extract transaction from here
@@ -377,6 +398,7 @@ func (repo *Events) CreateIncompleteBallots(eventid event.EventID, userid user.U
 			needIncomplete = FirstBatchCount
 		}
 
+		now := time.Now().UTC()
 		createKeys := []*datastore.Key{}
 		createBallots := []*event.Ballot{}
 		for _, teamresult := range teamresults {
@@ -386,10 +408,7 @@ func (repo *Events) CreateIncompleteBallots(eventid event.EventID, userid user.U
 			if teamresult.HasReviewer(userid) {
 				continue
 			}
-			if teamresult.HasMemberID(userid) {
-				continue
-			}
-			if !teamresult.HasSubmitted() {
+			if !eligible(teamresult.Team) {
 				continue
 			}
 
@@ -400,6 +419,8 @@ func (repo *Events) CreateIncompleteBallots(eventid event.EventID, userid user.U
 				Index:     int64(len(complete) + len(incomplete)),
 				Completed: false,
 				Aspects:   event.DefaultAspects,
+				Ref:       event.NewBallotRef(),
+				Assigned:  now,
 			}
 
 			ballotinfo := &event.BallotInfo{
@@ -426,6 +447,17 @@ func (repo *Events) CreateIncompleteBallots(eventid event.EventID, userid user.U
 func (repo *Events) SubmitBallot(eventid event.EventID, ballot *event.Ballot) error {
 	eventkey := newEventKey(eventid)
 	ballot.ID = newBallotKey(eventkey, ballot.Voter, ballot.Team)
+	if ballot.Ref == "" {
+		ballot.Ref = event.NewBallotRef()
+	}
+	if ballot.Completed {
+		now := time.Now().UTC()
+		if ballot.Submitted.IsZero() {
+			ballot.Submitted = now
+		} else {
+			ballot.Edited = now
+		}
+	}
 	_, err := repo.Client.Put(repo.Context, ballot.ID, ballot)
 	return eventsError(err)
 }
@@ -434,7 +466,10 @@ func (repo *Events) SubmitBallot(eventid event.EventID, ballot *event.Ballot) er
 func (repo *Events) UserBallot(eventid event.EventID, userid user.UserID, teamid event.TeamID) (*event.Ballot, error) {
 	eventkey := newEventKey(eventid)
 	ballot, err := repo.userBallot(eventkey, userid, teamid)
-	return ballot, eventsError(err)
+	if err != nil {
+		return nil, eventsError(err)
+	}
+	return ballot, nil
 }
 
 // UserBallots retrieves all user ballots.
@@ -481,7 +516,7 @@ func (repo *Events) Results(eventid event.EventID) ([]*event.TeamResult, error)
 	}
 
 	for _, result := range results {
-		result.Average, result.JammerAverage, result.JudgeAverage = event.AverageScores(result.Ballots, currentEvent)
+		result.Average, result.GroupAverages = event.AverageScores(result.Ballots, currentEvent)
 	}
 
 	return results, nil
@@ -500,3 +535,110 @@ func (repo *Events) TeamBallots(eventid event.EventID, teamid event.TeamID) ([]*
 	ballots, err := repo.teamBallots(eventkey, teamid)
 	return ballots, eventsError(err)
 }
+
+// AssignBallotRefs stores a new Ref for ballots that don't have one.
+func (repo *Events) AssignBallotRefs(eventid event.EventID, ballots []*event.Ballot) error {
+	eventkey := newEventKey(eventid)
+
+	keys := []*datastore.Key{}
+	missing := []*event.Ballot{}
+	for _, ballot := range ballots {
+		if ballot.Ref != "" {
+			continue
+		}
+		ballot.Ref = event.NewBallotRef()
+		ballot.ID = newBallotKey(eventkey, ballot.Voter, ballot.Team)
+		keys = append(keys, ballot.ID)
+		missing = append(missing, ballot)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := repo.Client.PutMulti(repo.Context, keys, missing)
+	return eventsError(err)
+}
+
+// Moderations retrieves all comment moderations of an event.
+func (repo *Events) Moderations(eventid event.EventID) ([]*event.Moderation, error) {
+	eventkey := newEventKey(eventid)
+	var moderations []*event.Moderation
+	q := datastore.NewQuery("Moderation").Ancestor(eventkey)
+	_, err := repo.Client.GetAll(repo.Context, q, &moderations)
+	return moderations, eventsError(err)
+}
+
+// UpdateModeration creates or updates a comment moderation.
+func (repo *Events) UpdateModeration(eventid event.EventID, moderation *event.Moderation) error {
+	eventkey := newEventKey(eventid)
+	key := newModerationKey(eventkey, moderation.Ref, moderation.Aspect)
+	_, err := repo.Client.Put(repo.Context, key, moderation)
+	return eventsError(err)
+}
+
+// Feedbacks retrieves all team responses to comments of an event.
+func (repo *Events) Feedbacks(eventid event.EventID) ([]*event.Feedback, error) {
+	eventkey := newEventKey(eventid)
+	var feedbacks []*event.Feedback
+	q := datastore.NewQuery("Feedback").Ancestor(eventkey)
+	_, err := repo.Client.GetAll(repo.Context, q, &feedbacks)
+	return feedbacks, eventsError(err)
+}
+
+// UpdateFeedback creates or updates a team response to a comment.
+func (repo *Events) UpdateFeedback(eventid event.EventID, feedback *event.Feedback) error {
+	eventkey := newEventKey(eventid)
+	key := newFeedbackKey(eventkey, feedback.Ref, feedback.Aspect)
+	_, err := repo.Client.Put(repo.Context, key, feedback)
+	return eventsError(err)
+}
+
+// Seekers retrieves all looking-for-team cards of an event.
+func (repo *Events) Seekers(eventid event.EventID) ([]*event.Seeker, error) {
+	eventkey := newEventKey(eventid)
+	var seekers []*event.Seeker
+	q := datastore.NewQuery("Seeker").Ancestor(eventkey)
+	_, err := repo.Client.GetAll(repo.Context, q, &seekers)
+	return seekers, eventsError(err)
+}
+
+// UpdateSeeker creates or updates a looking-for-team card.
+func (repo *Events) UpdateSeeker(eventid event.EventID, seeker *event.Seeker) error {
+	eventkey := newEventKey(eventid)
+	key := newSeekerKey(eventkey, seeker.User)
+	_, err := repo.Client.Put(repo.Context, key, seeker)
+	return eventsError(err)
+}
+
+// DeleteSeeker deletes the looking-for-team card of a user.
+func (repo *Events) DeleteSeeker(eventid event.EventID, userid user.UserID) error {
+	eventkey := newEventKey(eventid)
+	key := newSeekerKey(eventkey, userid)
+	err := repo.Client.Delete(repo.Context, key)
+	return eventsError(err)
+}
+
+// LinkStatuses retrieves link check results of all teams in an event.
+func (repo *Events) LinkStatuses(eventid event.EventID) ([]*event.LinkStatus, error) {
+	eventkey := newEventKey(eventid)
+	var statuses []*event.LinkStatus
+	q := datastore.NewQuery("LinkStatus").Ancestor(eventkey)
+	_, err := repo.Client.GetAll(repo.Context, q, &statuses)
+	return statuses, eventsError(err)
+}
+
+// UpdateLinkStatus creates or updates link check results of a team.
+func (repo *Events) UpdateLinkStatus(eventid event.EventID, status *event.LinkStatus) error {
+	eventkey := newEventKey(eventid)
+	key := newLinkStatusKey(eventkey, status.Team)
+	_, err := repo.Client.Put(repo.Context, key, status)
+	return eventsError(err)
+}
+
+// DeleteLinkStatus deletes link check results of a team.
+func (repo *Events) DeleteLinkStatus(eventid event.EventID, teamid event.TeamID) error {
+	eventkey := newEventKey(eventid)
+	key := newLinkStatusKey(eventkey, teamid)
+	err := repo.Client.Delete(repo.Context, key)
+	return eventsError(err)
+}
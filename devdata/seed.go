@@ -201,7 +201,7 @@ func Seed(log *slog.Logger, db *datastoredb.DB) {
 			uid := userIDs[(i*3+j+40)%len(userIDs)]
 			judges = append(judges, uid)
 		}
-		ev.Judges = judges
+		ev.Groups = []event.VoterGroup{{Name: "Judges", Weight: 30, Members: judges}}
 
 		if err := events.Create(ev); err != nil {
 			log.Error("seed: failed to create event", "id", def.ID, "error", err)
@@ -235,7 +235,10 @@ func Seed(log *slog.Logger, db *datastoredb.DB) {
 					Info: fmt.Sprintf("A game created for %s by team %s.", def.Name, tName),
 				},
 			}
-			team.Game.Link.Download = fmt.Sprintf("https://example.com/games/%s", strings.ReplaceAll(strings.ToLower(gName), " ", "-"))
+			team.Game.Links = []event.Link{{
+				Type: event.LinkDownload,
+				URL:  fmt.Sprintf("https://example.com/games/%s", strings.ReplaceAll(strings.ToLower(gName), " ", "-")),
+			}}
 
 			teamID, err := events.CreateTeam(ev.ID, team)
 			if err != nil {
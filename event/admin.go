@@ -33,22 +33,6 @@ func (server *Server) CreateEvent(context *Context) {
 		slug := context.FormValue("slug")
 		info := context.FormValue("info")
 
-		var judgePercentage float64
-		var err error
-
-		if context.FormValue("judgePercentage") == "" {
-			judgePercentage = 0
-		} else {
-			judgePercentage, err = strconv.ParseFloat(context.FormValue("judgePercentage"), 64)
-		}
-
-		if err != nil {
-			context.FlashErrorNow(err.Error())
-			context.Response.WriteHeader(http.StatusBadRequest)
-			context.Render("event-edit")
-			return
-		}
-
 		starttime := context.FormValue("StartTime")
 		endtime := context.FormValue("EndTime")
 
@@ -58,7 +42,6 @@ func (server *Server) CreateEvent(context *Context) {
 		event.Theme = theme
 		event.Info = info
 		event.Registration = true
-		event.JudgePercentage = judgePercentage
 
 		event.Created = time.Now().UTC()
 
@@ -105,7 +88,7 @@ func (server *Server) CreateEvent(context *Context) {
 
 		event.Organizers = append(event.Organizers, context.CurrentUser.ID)
 
-		err = context.Events.Create(event)
+		err := context.Events.Create(event)
 		if err != nil {
 			if err == ErrExists {
 				context.FlashErrorNow(fmt.Sprintf("Event with slug %q already exists.", event.ID))
@@ -143,14 +126,15 @@ func (server *Server) EditEvent(context *Context) {
 
 		theme := context.FormValue("theme")
 
-		var judgePercentage float64
-		var err error
-		if context.FormValue("judgePercentage") == "" {
-			judgePercentage = 0
-		} else {
-			judgePercentage, err = strconv.ParseFloat(context.FormValue("judgePercentage"), 64)
+		previousGroups, groups, err := parseGroupsForm(context)
+		if err != nil {
+			context.FlashErrorNow(err.Error())
+			context.Response.WriteHeader(http.StatusBadRequest)
+			context.Render("event-edit")
+			return
 		}
 
+		teamRules, err := parseTeamRules(context)
 		if err != nil {
 			context.FlashErrorNow(err.Error())
 			context.Response.WriteHeader(http.StatusBadRequest)
@@ -158,6 +142,22 @@ func (server *Server) EditEvent(context *Context) {
 			return
 		}
 
+		submission, err := parseSubmissionRules(context)
+		if err != nil {
+			context.FlashErrorNow(err.Error())
+			context.Response.WriteHeader(http.StatusBadRequest)
+			context.Render("event-edit")
+			return
+		}
+
+		tieBreakers := []TieBreaker{}
+		for i := range MaxTieBreakers {
+			breaker := TieBreaker(context.FormValue(fmt.Sprintf("TieBreaker[%v]", i)))
+			if breaker != "" {
+				tieBreakers = append(tieBreakers, breaker)
+			}
+		}
+
 		registration := context.FormValue("registration") == "true"
 		voting := context.FormValue("voting") == "true"
 		closed := context.FormValue("closed") == "true"
@@ -172,12 +172,16 @@ func (server *Server) EditEvent(context *Context) {
 
 		event := context.Event
 		event.Theme = theme
-		event.JudgePercentage = judgePercentage
 		event.Registration = registration
 		event.Voting = voting
 		event.Closed = closed
 		event.Revealed = revealed
 		event.Info = info
+		event.FlaggedWords = parseWordList(context.FormValue("FlaggedWords"))
+		event.SkipNoncompeting = context.FormValue("SkipNoncompeting") == "true"
+		event.RequireAssignment = context.FormValue("RequireAssignment") == "true"
+		event.TeamRules = teamRules
+		event.Submission = submission
 
 		if starttime == "" {
 			event.StartTime = time.Time{}
@@ -231,6 +235,22 @@ func (server *Server) EditEvent(context *Context) {
 			event.VotingCloses = t
 		}
 
+		if err := event.SetGroups(previousGroups, groups); err != nil {
+			context.FlashErrorNow(err.Error())
+			context.Response.WriteHeader(http.StatusBadRequest)
+			context.Render("event-edit")
+			return
+		}
+		tieBreakers = renameGroupTieBreakers(tieBreakers, previousGroups, groups)
+
+		if err := event.verifyTieBreakers(tieBreakers); err != nil {
+			context.FlashErrorNow(err.Error())
+			context.Response.WriteHeader(http.StatusBadRequest)
+			context.Render("event-edit")
+			return
+		}
+		event.TieBreakers = tieBreakers
+
 		err = context.Events.Update(event)
 		if err != nil {
 			context.FlashErrorNow(err.Error())
@@ -246,6 +266,33 @@ func (server *Server) EditEvent(context *Context) {
 	context.Render("event-edit")
 }
 
+// parseGroupsForm parses voter group names and weights from the event form.
+func parseGroupsForm(context *Context) (previous []string, groups []VoterGroup, err error) {
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("Group[%v].", i)
+		if _, ok := context.Request.Form[prefix+"Name"]; !ok {
+			break
+		}
+
+		name := context.FormValue(prefix + "Name")
+		if name == "" {
+			continue
+		}
+
+		weight := 0.0
+		if weightstr := context.FormValue(prefix + "Weight"); weightstr != "" {
+			weight, err = strconv.ParseFloat(weightstr, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid weight for voter group %q: %w", name, err)
+			}
+		}
+
+		previous = append(previous, context.FormValue(prefix+"Previous"))
+		groups = append(groups, VoterGroup{Name: name, Weight: weight})
+	}
+	return previous, groups, nil
+}
+
 // Jammers handles managing registered jammers for an event.
 func (server *Server) Jammers(context *Context) {
 	if !context.CurrentUser.IsAdmin() {
@@ -270,15 +317,14 @@ func (server *Server) Jammers(context *Context) {
 
 		jammersAdded := []user.UserID{}
 		jammersRemoved := []user.UserID{}
-		judgesAdded := []user.UserID{}
-		judgesRemoved := []user.UserID{}
+		groupsChanged := map[user.UserID]string{}
 
 		for _, u := range users {
 			jammersBefore := context.FormValue(fmt.Sprintf("%v.Jammer.Start", u.ID)) == "approved"
 			jammersAfter := context.FormValue(fmt.Sprintf("%v.Jammer", u.ID)) == "approved"
 
-			judgesBefore := context.FormValue(fmt.Sprintf("%v.Judge.Start", u.ID)) == "isjudge"
-			judgesAfter := context.FormValue(fmt.Sprintf("%v.Judge", u.ID)) == "isjudge"
+			groupBefore := context.FormValue(fmt.Sprintf("%v.Group.Start", u.ID))
+			groupAfter := context.FormValue(fmt.Sprintf("%v.Group", u.ID))
 
 			if jammersBefore != jammersAfter {
 				if jammersAfter {
@@ -288,18 +334,21 @@ func (server *Server) Jammers(context *Context) {
 				}
 			}
 
-			if judgesBefore != judgesAfter {
-				if judgesAfter {
-					judgesAdded = append(judgesAdded, u.ID)
-				} else {
-					judgesRemoved = append(judgesRemoved, u.ID)
-				}
+			if groupBefore != groupAfter {
+				groupsChanged[u.ID] = groupAfter
 			}
 		}
 
 		event := context.Event
 		event.AddRemoveJammers(jammersAdded, jammersRemoved)
-		event.AddRemoveJudges(judgesAdded, judgesRemoved)
+		for userid, group := range groupsChanged {
+			if err := event.SetVoterGroup(userid, group); err != nil {
+				context.FlashErrorNow(err.Error())
+				context.Response.WriteHeader(http.StatusBadRequest)
+				context.Render("event-jammers")
+				return
+			}
+		}
 
 		err := context.Events.Update(event)
 		if err != nil {
@@ -312,14 +361,11 @@ func (server *Server) Jammers(context *Context) {
 		if len(jammersRemoved) > 0 {
 			context.FlashMessage(fmt.Sprintf("Removed %v jammers.", len(jammersRemoved)))
 		}
-		if len(judgesRemoved) > 0 {
-			context.FlashMessage(fmt.Sprintf("Removed %v judges.", len(judgesRemoved)))
-		}
 		if len(jammersAdded) > 0 {
 			context.FlashMessage(fmt.Sprintf("Added %v jammers.", len(jammersAdded)))
 		}
-		if len(judgesAdded) > 0 {
-			context.FlashMessage(fmt.Sprintf("Added %v judges.", len(judgesAdded)))
+		if len(groupsChanged) > 0 {
+			context.FlashMessage(fmt.Sprintf("Moved %v voters between groups.", len(groupsChanged)))
 		}
 
 		context.Redirect(string(event.Path()), http.StatusSeeOther)
@@ -360,6 +406,7 @@ func (server *Server) BallotsCSV(context *Context) {
 		context.Error(err.Error(), http.StatusInternalServerError)
 		return
 	}
+	users = append(users, context.Event.PlaceholderUsers()...)
 	userbyid := map[user.UserID]*user.User{}
 	for _, user := range users {
 		userbyid[user.ID] = user
@@ -380,15 +427,35 @@ func (server *Server) BallotsCSV(context *Context) {
 		"Innovation",
 		"Bonus",
 		"Overall",
+		"Assigned",
+		"Opened",
+		"Submitted",
+		"Edited",
+		"SecondsSpent",
+		"Proxy",
+		"EnteredBy",
 	})
 
 	for _, ballot := range ballots {
 		if !ballot.Completed {
 			continue
 		}
-		voter := userbyid[ballot.Voter]
+		voter, ok := userbyid[ballot.Voter]
+		if !ok {
+			voter = &user.User{ID: ballot.Voter}
+		}
 		team := teambyid[ballot.Team]
 
+		enteredBy := ""
+		if ballot.Proxy {
+			enteredBy = ballot.EnteredBy.String()
+		}
+
+		secondsSpent := ""
+		if spent, ok := ballot.TimeSpent(); ok {
+			secondsSpent = strconv.Itoa(int(spent.Seconds()))
+		}
+
 		_ = writer.Write([]string{
 			voter.ID.String(),
 			voter.Name,
@@ -401,6 +468,21 @@ func (server *Server) BallotsCSV(context *Context) {
 			fmt.Sprintf("%.1f", ballot.Innovation.Score),
 			fmt.Sprintf("%.1f", ballot.Bonus.Score),
 			fmt.Sprintf("%.2f", ballot.Overall.Score),
+			formatCSVTime(ballot.Assigned),
+			formatCSVTime(ballot.Opened),
+			formatCSVTime(ballot.Submitted),
+			formatCSVTime(ballot.Edited),
+			secondsSpent,
+			strconv.FormatBool(ballot.Proxy),
+			enteredBy,
 		})
 	}
 }
+
+// formatCSVTime formats t for exports, zero time is left empty.
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,414 @@
+package event
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// AnomalyKind describes the type of a suspicious voting pattern.
+type AnomalyKind string
+
+const (
+	// AnomalyOutlier is a voter whose scores deviate strongly from the consensus.
+	AnomalyOutlier AnomalyKind = "Outlier"
+	// AnomalyFlat is a voter who gives near identical scores to everything.
+	AnomalyFlat AnomalyKind = "Flat"
+	// AnomalyFast is a voter who submits ballots suspiciously quickly.
+	AnomalyFast AnomalyKind = "Fast"
+	// AnomalyMutual is a pair of teams scoring each other well above the consensus.
+	AnomalyMutual AnomalyKind = "Mutual"
+)
+
+// AnomalyOptions contains thresholds for DetectAnomalies.
+type AnomalyOptions struct {
+	// MinBallots is the minimum number of ballots before judging a voter.
+	MinBallots int
+	// OutlierDeviation is the mean absolute difference from consensus.
+	OutlierDeviation float64
+	// FlatSpread is the maximum standard deviation of all scores.
+	FlatSpread float64
+	// FastInterval is the minimum expected time for playing and voting a game.
+	FastInterval time.Duration
+	// FastCount is the number of fast submissions before flagging.
+	FastCount int
+	// MutualDeviation is the mean difference from consensus in both directions.
+	MutualDeviation float64
+}
+
+// DefaultAnomalyOptions contains the thresholds used by the report.
+var DefaultAnomalyOptions = AnomalyOptions{
+	MinBallots:       3,
+	OutlierDeviation: 1.25,
+	FlatSpread:       0.15,
+	FastInterval:     30 * time.Second,
+	FastCount:        2,
+	MutualDeviation:  0.75,
+}
+
+// Anomaly is a single suspicious voting pattern.
+type Anomaly struct {
+	Kind AnomalyKind
+	// Voter is the flagged voter, zero for team anomalies.
+	Voter user.UserID
+	// Teams are the flagged teams for team anomalies.
+	Teams []TeamID
+	// Severity is used for sorting anomalies of the same kind.
+	Severity float64
+	Details  string
+	Ballots  []*BallotReview
+}
+
+// BallotReview is a ballot compared to the consensus of other voters.
+type BallotReview struct {
+	Ballot *Ballot
+	Team   *Team
+
+	// Consensus is the average overall score of other voters.
+	Consensus    float64
+	HasConsensus bool
+	// Deviation is the difference from consensus.
+	Deviation float64
+}
+
+// TimeSpentText returns time spent on the ballot, if it is known.
+func (review *BallotReview) TimeSpentText() string {
+	if spent, ok := review.Ballot.TimeSpent(); ok {
+		return formatDuration(spent)
+	}
+	return ""
+}
+
+// consensus contains overall score sums for each team.
+type consensus struct {
+	sum   map[TeamID]float64
+	count map[TeamID]int
+}
+
+// without returns the consensus for ballot team excluding the ballot itself.
+func (c *consensus) without(ballot *Ballot) (float64, bool) {
+	count := c.count[ballot.Team] - 1
+	if count < 2 {
+		return 0, false
+	}
+	return (c.sum[ballot.Team] - ballot.Overall.Score) / float64(count), true
+}
+
+// ReviewBallots compares completed ballots to the consensus of other voters.
+//
+// Ballots for the voter's own team are left out.
+func ReviewBallots(ballots []*Ballot, teams []*Team) []*BallotReview {
+	teambyid := map[TeamID]*Team{}
+	for _, team := range teams {
+		teambyid[team.ID] = team
+	}
+
+	counted := []*Ballot{}
+	c := consensus{sum: map[TeamID]float64{}, count: map[TeamID]int{}}
+	for _, ballot := range ballots {
+		team, ok := teambyid[ballot.Team]
+		if !ok || !ballot.Completed || team.HasMemberID(ballot.Voter) {
+			continue
+		}
+		counted = append(counted, ballot)
+		c.sum[ballot.Team] += ballot.Overall.Score
+		c.count[ballot.Team]++
+	}
+
+	reviews := make([]*BallotReview, 0, len(counted))
+	for _, ballot := range counted {
+		review := &BallotReview{
+			Ballot: ballot,
+			Team:   teambyid[ballot.Team],
+		}
+		review.Consensus, review.HasConsensus = c.without(ballot)
+		if review.HasConsensus {
+			review.Deviation = ballot.Overall.Score - review.Consensus
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews
+}
+
+// DetectAnomalies finds suspicious voting patterns in ballots.
+func DetectAnomalies(ballots []*Ballot, teams []*Team, options AnomalyOptions) []*Anomaly {
+	reviews := ReviewBallots(ballots, teams)
+
+	byvoter := map[user.UserID][]*BallotReview{}
+	voters := []user.UserID{}
+	for _, review := range reviews {
+		voter := review.Ballot.Voter
+		if _, ok := byvoter[voter]; !ok {
+			voters = append(voters, voter)
+		}
+		byvoter[voter] = append(byvoter[voter], review)
+	}
+
+	anomalies := []*Anomaly{}
+	for _, voter := range voters {
+		voterReviews := byvoter[voter]
+		if len(voterReviews) < options.MinBallots {
+			continue
+		}
+		if anomaly := detectOutlier(voter, voterReviews, options); anomaly != nil {
+			anomalies = append(anomalies, anomaly)
+		}
+		if anomaly := detectFlat(voter, voterReviews, options); anomaly != nil {
+			anomalies = append(anomalies, anomaly)
+		}
+		if anomaly := detectFast(voter, voterReviews, options); anomaly != nil {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	anomalies = append(anomalies, detectMutual(reviews, teams, options)...)
+
+	kindOrder := map[AnomalyKind]int{AnomalyOutlier: 0, AnomalyFlat: 1, AnomalyFast: 2, AnomalyMutual: 3}
+	sort.SliceStable(anomalies, func(i, k int) bool {
+		a, b := anomalies[i], anomalies[k]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.Severity > b.Severity
+	})
+
+	return anomalies
+}
+
+// detectOutlier flags voters who are far from the consensus.
+func detectOutlier(voter user.UserID, reviews []*BallotReview, options AnomalyOptions) *Anomaly {
+	count := 0
+	signed, absolute := 0.0, 0.0
+	for _, review := range reviews {
+		if !review.HasConsensus {
+			continue
+		}
+		count++
+		signed += review.Deviation
+		absolute += math.Abs(review.Deviation)
+	}
+	if count < options.MinBallots {
+		return nil
+	}
+
+	signed /= float64(count)
+	absolute /= float64(count)
+	if absolute < options.OutlierDeviation {
+		return nil
+	}
+
+	direction := "above"
+	if signed < 0 {
+		direction = "below"
+	}
+	return &Anomaly{
+		Kind:     AnomalyOutlier,
+		Voter:    voter,
+		Severity: absolute,
+		Details: fmt.Sprintf("Differs from consensus by %.2f on average, mostly %s (%+.2f) over %v ballots.",
+			absolute, direction, signed, count),
+		Ballots: reviews,
+	}
+}
+
+// detectFlat flags voters who give the same scores to everything.
+func detectFlat(voter user.UserID, reviews []*BallotReview, options AnomalyOptions) *Anomaly {
+	scores := []float64{}
+	for _, review := range reviews {
+		aspects := &review.Ballot.Aspects
+		scores = append(scores,
+			aspects.Theme.Score,
+			aspects.Enjoyment.Score,
+			aspects.Aesthetics.Score,
+			aspects.Innovation.Score)
+	}
+
+	mean, stddev := meanStddev(scores)
+	if stddev > options.FlatSpread {
+		return nil
+	}
+
+	return &Anomaly{
+		Kind:     AnomalyFlat,
+		Voter:    voter,
+		Severity: options.FlatSpread - stddev,
+		Details: fmt.Sprintf("All scores are around %.1f (spread %.2f) over %v ballots.",
+			mean, stddev, len(reviews)),
+		Ballots: reviews,
+	}
+}
+
+// detectFast flags voters who submit ballots too quickly.
+//
+// Ballots with a known opening time are measured from opening to submission,
+// older ballots are measured from the previous submission.
+// Proxy ballots are skipped, since they are entered by an admin after voting.
+func detectFast(voter user.UserID, reviews []*BallotReview, options AnomalyOptions) *Anomaly {
+	submitted := []*BallotReview{}
+	for _, review := range reviews {
+		if !review.Ballot.Submitted.IsZero() && !review.Ballot.Proxy {
+			submitted = append(submitted, review)
+		}
+	}
+	sort.Slice(submitted, func(i, k int) bool {
+		return submitted[i].Ballot.Submitted.Before(submitted[k].Ballot.Submitted)
+	})
+
+	fast := []*BallotReview{}
+	for i, review := range submitted {
+		if spent, ok := review.Ballot.TimeSpent(); ok {
+			if spent < options.FastInterval {
+				fast = append(fast, review)
+			}
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+		gap := review.Ballot.Submitted.Sub(submitted[i-1].Ballot.Submitted)
+		if gap < options.FastInterval {
+			fast = append(fast, review)
+		}
+	}
+	if len(fast) < options.FastCount {
+		return nil
+	}
+
+	return &Anomaly{
+		Kind:     AnomalyFast,
+		Voter:    voter,
+		Severity: float64(len(fast)) / float64(len(submitted)),
+		Details: fmt.Sprintf("%v of %v ballots were submitted in less than %v.",
+			len(fast), len(submitted), options.FastInterval),
+		Ballots: fast,
+	}
+}
+
+// detectMutual flags pairs of teams whose members score each other well above consensus.
+func detectMutual(reviews []*BallotReview, teams []*Team, options AnomalyOptions) []*Anomaly {
+	type pair struct{ from, to TeamID }
+	deviation := map[pair]float64{}
+	count := map[pair]int{}
+	ballots := map[pair][]*BallotReview{}
+
+	for _, review := range reviews {
+		if !review.HasConsensus {
+			continue
+		}
+		for _, team := range teams {
+			if team.ID == review.Team.ID || !team.HasMemberID(review.Ballot.Voter) {
+				continue
+			}
+			p := pair{from: team.ID, to: review.Team.ID}
+			deviation[p] += review.Deviation
+			count[p]++
+			ballots[p] = append(ballots[p], review)
+		}
+	}
+
+	anomalies := []*Anomaly{}
+	for i, a := range teams {
+		for _, b := range teams[i+1:] {
+			ab, ba := pair{a.ID, b.ID}, pair{b.ID, a.ID}
+			if count[ab] == 0 || count[ba] == 0 {
+				continue
+			}
+			devab := deviation[ab] / float64(count[ab])
+			devba := deviation[ba] / float64(count[ba])
+			if devab < options.MutualDeviation || devba < options.MutualDeviation {
+				continue
+			}
+
+			anomalies = append(anomalies, &Anomaly{
+				Kind:     AnomalyMutual,
+				Teams:    []TeamID{a.ID, b.ID},
+				Severity: math.Min(devab, devba),
+				Details: fmt.Sprintf("%v scored %v %+.2f above consensus, %v scored %v %+.2f above consensus.",
+					a.Name, b.Name, devab, b.Name, a.Name, devba),
+				Ballots: append(append([]*BallotReview{}, ballots[ab]...), ballots[ba]...),
+			})
+		}
+	}
+	return anomalies
+}
+
+// meanStddev calculates mean and standard deviation of xs.
+func meanStddev(xs []float64) (mean, stddev float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	for _, x := range xs {
+		stddev += (x - mean) * (x - mean)
+	}
+	stddev = math.Sqrt(stddev / float64(len(xs)))
+	return mean, stddev
+}
+
+// Anomalies displays a report of suspicious voting patterns.
+func (server *Server) Anomalies(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to view anomalies.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	ballots, err := context.Events.Ballots(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	context.Data["UserNames"] = userNames(append(users, context.Event.PlaceholderUsers()...))
+
+	if voterid, ok := context.IntParam("userid"); ok {
+		voter := user.UserID(voterid)
+		reviews := []*BallotReview{}
+		for _, review := range ReviewBallots(ballots, teams) {
+			if review.Ballot.Voter == voter {
+				reviews = append(reviews, review)
+			}
+		}
+		sort.Slice(reviews, func(i, k int) bool {
+			return reviews[i].Deviation < reviews[k].Deviation
+		})
+
+		context.Data["Voter"] = voter
+		context.Data["Reviews"] = reviews
+		context.Render("event-anomalies-voter")
+		return
+	}
+
+	teamnames := map[TeamID]string{}
+	for _, team := range teams {
+		teamnames[team.ID] = team.Name
+	}
+
+	context.Data["TeamNames"] = teamnames
+	context.Data["Options"] = DefaultAnomalyOptions
+	context.Data["Anomalies"] = DetectAnomalies(ballots, teams, DefaultAnomalyOptions)
+	context.Render("event-anomalies")
+}
+
+// userNames creates a lookup table for user names.
+func userNames(users []*user.User) map[user.UserID]string {
+	names := map[user.UserID]string{}
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	return names
+}
@@ -0,0 +1,215 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// anomalyBallot creates a completed ballot, where spread varies the aspect scores.
+func anomalyBallot(voter user.UserID, team TeamID, overall, spread float64) *Ballot {
+	return &Ballot{
+		Voter:     voter,
+		Team:      team,
+		Completed: true,
+		Aspects: Aspects{
+			Theme:      Aspect{Score: overall + spread},
+			Enjoyment:  Aspect{Score: overall - spread},
+			Aesthetics: Aspect{Score: overall},
+			Innovation: Aspect{Score: overall},
+			Overall:    Aspect{Score: overall},
+		},
+	}
+}
+
+// timedBallot sets the opening and submission time of the ballot.
+func timedBallot(ballot *Ballot, opened, submitted time.Time) *Ballot {
+	ballot.Opened = opened
+	ballot.Submitted = submitted
+	return ballot
+}
+
+// proxyBallot marks the ballot as entered by an admin.
+func proxyBallot(ballot *Ballot) *Ballot {
+	ballot.Proxy = true
+	return ballot
+}
+
+func describeAnomaly(anomaly *Anomaly) string {
+	if anomaly.Kind == AnomalyMutual {
+		return fmt.Sprintf("%v:%v-%v", anomaly.Kind, anomaly.Teams[0], anomaly.Teams[1])
+	}
+	return fmt.Sprintf("%v:%v", anomaly.Kind, anomaly.Voter)
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	teams := []*Team{}
+	for id := TeamID(1); id <= 4; id++ {
+		teams = append(teams, &Team{
+			ID:      id,
+			Name:    fmt.Sprintf("Team %v", id),
+			Members: []Member{{ID: user.UserID(100 + id)}},
+		})
+	}
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	each := func(voter user.UserID, score func(team TeamID) float64, spread float64) []*Ballot {
+		ballots := []*Ballot{}
+		for _, team := range teams {
+			ballots = append(ballots, anomalyBallot(voter, team.ID, score(team.ID), spread))
+		}
+		return ballots
+	}
+	constant := func(value float64) func(TeamID) float64 {
+		return func(TeamID) float64 { return value }
+	}
+	rising := func(team TeamID) float64 { return 1 + float64(team) }
+
+	tests := []struct {
+		name    string
+		ballots []*Ballot
+		expect  []string
+	}{
+		{
+			name: "consensus",
+			ballots: slices.Concat(
+				each(1, rising, 1),
+				each(2, rising, 1),
+				each(3, rising, 0.5),
+			),
+		},
+		{
+			name: "outlier",
+			ballots: slices.Concat(
+				each(1, constant(4), 1),
+				each(2, constant(4), 1),
+				each(3, constant(4), 1),
+				each(9, constant(1), 0.5),
+			),
+			expect: []string{"Outlier:9"},
+		},
+		{
+			name: "flat",
+			ballots: slices.Concat(
+				each(1, rising, 1),
+				each(2, rising, 1),
+				each(8, constant(3), 0),
+			),
+			expect: []string{"Flat:8"},
+		},
+		{
+			name: "flat below minimum ballots",
+			ballots: slices.Concat(
+				each(1, rising, 1),
+				each(2, rising, 1),
+				each(8, constant(3), 0)[:2],
+			),
+		},
+		{
+			name: "fast since opening",
+			ballots: []*Ballot{
+				timedBallot(anomalyBallot(1, 1, 3, 1), base, base.Add(10*time.Second)),
+				timedBallot(anomalyBallot(1, 2, 4, 1), base.Add(time.Minute), base.Add(time.Minute+5*time.Second)),
+				timedBallot(anomalyBallot(1, 3, 2, 1), base.Add(2*time.Minute), base.Add(5*time.Minute)),
+			},
+			expect: []string{"Fast:1"},
+		},
+		{
+			name: "fast between legacy submissions",
+			ballots: []*Ballot{
+				timedBallot(anomalyBallot(1, 1, 3, 1), time.Time{}, base),
+				timedBallot(anomalyBallot(1, 2, 4, 1), time.Time{}, base.Add(5*time.Second)),
+				timedBallot(anomalyBallot(1, 3, 2, 1), time.Time{}, base.Add(10*time.Second)),
+			},
+			expect: []string{"Fast:1"},
+		},
+		{
+			name: "proxy ballots entered back to back",
+			ballots: []*Ballot{
+				proxyBallot(timedBallot(anomalyBallot(1, 1, 3, 1), time.Time{}, base)),
+				proxyBallot(timedBallot(anomalyBallot(1, 2, 4, 1), time.Time{}, base.Add(5*time.Second))),
+				proxyBallot(timedBallot(anomalyBallot(1, 3, 2, 1), time.Time{}, base.Add(10*time.Second))),
+				proxyBallot(timedBallot(anomalyBallot(1, 4, 3, 1), time.Time{}, base.Add(15*time.Second))),
+			},
+		},
+		{
+			name: "slow",
+			ballots: []*Ballot{
+				timedBallot(anomalyBallot(1, 1, 3, 1), base, base.Add(5*time.Minute)),
+				timedBallot(anomalyBallot(1, 2, 4, 1), base.Add(10*time.Minute), base.Add(15*time.Minute)),
+				timedBallot(anomalyBallot(1, 3, 2, 1), time.Time{}, base.Add(20*time.Minute)),
+			},
+		},
+		{
+			name: "submitted without opening",
+			ballots: []*Ballot{
+				timedBallot(anomalyBallot(1, 1, 3, 1), base, base),
+				timedBallot(anomalyBallot(1, 2, 4, 1), base.Add(5*time.Minute), base.Add(5*time.Minute)),
+				timedBallot(anomalyBallot(1, 3, 2, 1), base.Add(10*time.Minute), base.Add(10*time.Minute)),
+			},
+		},
+		{
+			name: "mutual",
+			ballots: slices.Concat(
+				each(1, constant(3), 1),
+				each(2, constant(3), 1),
+				each(3, constant(3), 1),
+				[]*Ballot{
+					anomalyBallot(101, 2, 5, 1),
+					anomalyBallot(102, 1, 5, 1),
+				},
+			),
+			expect: []string{"Mutual:1-2"},
+		},
+		{
+			name: "own team ignored",
+			ballots: slices.Concat(
+				each(1, constant(3), 1),
+				each(2, constant(3), 1),
+				each(3, constant(3), 1),
+				[]*Ballot{
+					anomalyBallot(101, 1, 5, 1),
+					anomalyBallot(102, 2, 5, 1),
+				},
+			),
+		},
+		{
+			name: "ordered by kind",
+			ballots: slices.Concat(
+				each(1, constant(4), 1),
+				each(2, constant(4), 1),
+				each(3, constant(4), 1),
+				each(4, constant(4), 1),
+				each(5, constant(4), 1),
+				each(8, constant(1), 0),
+				each(9, constant(1), 0.5),
+			),
+			expect: []string{"Outlier:8", "Outlier:9", "Flat:8"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for _, anomaly := range DetectAnomalies(test.ballots, teams, DefaultAnomalyOptions) {
+				got = append(got, describeAnomaly(anomaly))
+			}
+			if !slices.Equal(got, test.expect) {
+				t.Errorf("got %v, expected %v", got, test.expect)
+			}
+		})
+	}
+}
+
+func TestMeanStddev(t *testing.T) {
+	mean, stddev := meanStddev([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if mean != 5 || stddev != 2 {
+		t.Errorf("got %v and %v", mean, stddev)
+	}
+	if mean, stddev := meanStddev(nil); mean != 0 || stddev != 0 {
+		t.Errorf("empty: got %v and %v", mean, stddev)
+	}
+}
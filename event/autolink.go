@@ -0,0 +1,129 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/adinfinit/jamvote/internal/fuzzy"
+	"github.com/adinfinit/jamvote/user"
+)
+
+// AutoLinkConfidence is the minimum similarity for offering a member to a user.
+const AutoLinkConfidence = 0.9
+
+// MemberMatch is an unregistered member whose name matches a user.
+type MemberMatch struct {
+	Event      *Event
+	Team       *Team
+	Member     Member
+	Confidence float64
+}
+
+// Key identifies the match in forms.
+func (match *MemberMatch) Key() string {
+	return fmt.Sprintf("%v/%v/%v", match.Event.ID, match.Team.ID, match.Member.Name)
+}
+
+// Percent returns the confidence as a percentage.
+func (match *MemberMatch) Percent() int {
+	return int(match.Confidence*100 + 0.5)
+}
+
+// FindMemberMatches finds unregistered members in all events matching the name of u.
+//
+// Only events that are open for registration are searched, teams where
+// u has already requested to join are skipped.
+func FindMemberMatches(repo Repo, u *user.User) ([]MemberMatch, error) {
+	matches := []MemberMatch{}
+	if u == nil || u.Name == "" {
+		return matches, nil
+	}
+
+	events, err := repo.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if event.Closed || !event.Registration {
+			continue
+		}
+		teams, err := repo.Teams(event.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range teams {
+			if team.HasMember(u) || team.HasJoinRequest(u.ID) {
+				continue
+			}
+			for _, member := range team.Members {
+				if member.ID != 0 {
+					continue
+				}
+				confidence := fuzzy.Similarity(member.Name, u.Name)
+				if confidence >= AutoLinkConfidence {
+					matches = append(matches, MemberMatch{
+						Event:      event,
+						Team:       team,
+						Member:     member,
+						Confidence: confidence,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, k int) bool {
+		return matches[i].Event.Less(matches[k].Event)
+	})
+	return matches, nil
+}
+
+// RequestMemberMatches asks to join the teams of matches selected by keys.
+//
+// The requests name the matched member, so that approving the request links
+// the member to u. Team members and organizers are notified.
+func RequestMemberMatches(repo Repo, u *user.User, keys []string) ([]MemberMatch, error) {
+	matches, err := FindMemberMatches(repo, u)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := []MemberMatch{}
+	for _, match := range matches {
+		if !slices.Contains(keys, match.Key()) {
+			continue
+		}
+		// a user can only take a single place in a team
+		if match.Team.HasJoinRequest(u.ID) {
+			continue
+		}
+		teams, err := repo.Teams(match.Event.ID)
+		if err != nil {
+			return requested, err
+		}
+		if err := match.Event.TeamRules.CanJoin(match.Team, u, teams); err != nil {
+			return requested, fmt.Errorf("%v: %w", match.Event.Name, err)
+		}
+		if err := match.Team.AddJoinRequest(u, match.Member.Name); err != nil {
+			return requested, err
+		}
+		if err := repo.UpdateTeam(match.Event.ID, match.Team); err != nil {
+			return requested, err
+		}
+		requested = append(requested, match)
+
+		event, err := repo.ByID(match.Event.ID)
+		if err != nil {
+			return requested, err
+		}
+		event.Notify(
+			fmt.Sprintf("%v asked to be linked to %q in team %v.", u.Name, match.Member.Name, match.Team.Name),
+			event.Path("team", match.Team.ID),
+		)
+		if err := repo.Update(event); err != nil {
+			return requested, err
+		}
+	}
+	return requested, nil
+}
@@ -2,6 +2,7 @@ package event
 
 import (
 	"fmt"
+	"time"
 
 	"cloud.google.com/go/datastore"
 
@@ -11,12 +12,17 @@ import (
 // BallotRepo is used to manage ballots for an event.
 type BallotRepo interface {
 	Ballots(eventid EventID) ([]*Ballot, error)
-	CreateIncompleteBallots(eventid EventID, userid user.UserID) (complete, incomplete []*BallotInfo, err error)
+	// CreateIncompleteBallots fills the voting queue with eligible teams.
+	CreateIncompleteBallots(eventid EventID, userid user.UserID, eligible func(*Team) bool) (complete, incomplete []*BallotInfo, err error)
 	SubmitBallot(eventid EventID, ballot *Ballot) error
+	// UserBallot returns a nil ballot with ErrNotExists when there is none.
 	UserBallot(eventid EventID, userid user.UserID, teamid TeamID) (*Ballot, error)
 	UserBallots(eventid EventID, userid user.UserID) ([]*BallotInfo, error)
 	Results(eventid EventID) ([]*TeamResult, error)
 	TeamBallots(eventid EventID, teamid TeamID) ([]*Ballot, error)
+	// AssignBallotRefs stores a new Ref for ballots that don't have one,
+	// leaving other fields untouched.
+	AssignBallotRefs(eventid EventID, ballots []*Ballot) error
 }
 
 // Ballot is all information for a single ballot.
@@ -27,6 +33,41 @@ type Ballot struct {
 	Index     int64 `datastore:",noindex"`
 	Completed bool  `datastore:",noindex"`
 	Aspects
+
+	// Ref identifies the ballot without revealing the voter.
+	Ref string `datastore:",noindex"`
+
+	// Assigned is the time when the ballot was added to the voting queue.
+	Assigned time.Time `datastore:",noindex"`
+	// Opened is the time when the voter first opened the ballot.
+	Opened time.Time `datastore:",noindex"`
+	// Submitted is the time when the ballot was first completed.
+	Submitted time.Time `datastore:",noindex"`
+	// Edited is the time when the completed ballot was last changed.
+	Edited time.Time `datastore:",noindex"`
+
+	// Proxy is set when an admin entered the ballot on behalf of the voter.
+	Proxy bool `datastore:",noindex"`
+	// EnteredBy is the admin who entered the proxy ballot.
+	EnteredBy user.UserID `datastore:",noindex"`
+}
+
+// TimeSpent returns the time between opening and submitting the ballot.
+//
+// Ballots submitted without being opened first have no known time spent.
+func (ballot *Ballot) TimeSpent() (time.Duration, bool) {
+	if ballot.Opened.IsZero() || ballot.Submitted.IsZero() || !ballot.Submitted.After(ballot.Opened) {
+		return 0, false
+	}
+	return ballot.Submitted.Sub(ballot.Opened), true
+}
+
+// TimeWaiting returns the time between assigning and opening the ballot.
+func (ballot *Ballot) TimeWaiting() (time.Duration, bool) {
+	if ballot.Assigned.IsZero() || ballot.Opened.IsZero() || ballot.Opened.Before(ballot.Assigned) {
+		return 0, false
+	}
+	return ballot.Opened.Sub(ballot.Assigned), true
 }
 
 // BallotInfo is a single ballot, but contains a reference to the target team.
@@ -40,14 +81,19 @@ type TeamResult struct {
 	*Team
 	Ballots []*Ballot
 
-	Average       Aspects
-	JudgeAverage  Aspects
-	JammerAverage Aspects
+	Average Aspects
+	// GroupAverages contains averages for each of Event.ScoreGroups.
+	GroupAverages []GroupAverage
 
 	Pending  int
 	Complete int
 
 	MemberBallots []*Ballot
+
+	// Place is the final placement, zero for teams that are not ranked.
+	Place int
+	// SharedPlace is set when the team is tied with another team.
+	SharedPlace bool
 }
 
 // HasReviewer checks whether team results contains userid.
@@ -60,6 +106,45 @@ func (info *TeamResult) HasReviewer(userid user.UserID) bool {
 	return false
 }
 
+// GroupAverage returns averages of the named voter group.
+func (info *TeamResult) GroupAverage(name string) GroupAverage {
+	for _, group := range info.GroupAverages {
+		if group.Name == name {
+			return group
+		}
+	}
+	return GroupAverage{Name: name}
+}
+
+// Unscored returns whether the team was only reviewed by groups with zero weight.
+//
+// Such teams have completed ballots, but no final score.
+func (info *TeamResult) Unscored() bool {
+	counted := false
+	for _, group := range info.GroupAverages {
+		if group.Count > 0 {
+			counted = true
+			if group.Weight > 0 {
+				return false
+			}
+		}
+	}
+	return counted
+}
+
+// IsRanked checks whether the team gets a placement.
+func (info *TeamResult) IsRanked(rules SubmissionRules) bool {
+	return info.IsCompeting(rules) && !info.Unscored()
+}
+
+// GroupAverage contains averages of a single voter group.
+type GroupAverage struct {
+	Name    string
+	Weight  float64
+	Count   int
+	Average Aspects
+}
+
 // DefaultAspects contains defaults for aspects.
 var DefaultAspects = Aspects{
 	Theme:      Aspect{3, ""},
@@ -70,46 +155,61 @@ var DefaultAspects = Aspects{
 	Overall:    Aspect{0, ""},
 }
 
-// AverageScores returns averages for all aspects.
-func AverageScores(ballots []*Ballot, event *Event) (final, jammers, judges Aspects) {
-	judgeCount := 0.0
-	jammerCount := 0.0
+// AverageScores returns averages for all aspects and for each voter group.
+//
+// The final score is weighted by group weights, groups without any
+// completed ballots are ignored and the remaining weights are rescaled.
+// When only groups with zero weight have completed ballots the final score
+// is left empty, see TeamResult.Unscored.
+func AverageScores(ballots []*Ballot, event *Event) (final Aspects, groups []GroupAverage) {
+	scoreGroups := event.ScoreGroups()
+	groups = make([]GroupAverage, len(scoreGroups))
+	for i, group := range scoreGroups {
+		groups[i].Name = group.Name
+		groups[i].Weight = group.Weight
+	}
+
 	for _, ballot := range ballots {
 		if !ballot.Completed {
 			continue
 		}
 
-		if event.JudgePercentage > 0 && event.HasJudgeById(&ballot.Voter) {
-			judges.Add(&ballot.Aspects)
-			judgeCount += 1.0
-		} else {
-			jammers.Add(&ballot.Aspects)
-			jammerCount += 1.0
+		index := 0
+		for i, group := range scoreGroups {
+			if group.HasMember(ballot.Voter) {
+				index = i
+				break
+			}
 		}
-	}
 
-	if jammerCount > 0 {
-		jammers.Scale(1 / jammerCount)
-	}
-	if judgeCount > 0 {
-		judges.Scale(1 / judgeCount)
+		groups[index].Average.Add(&ballot.Aspects)
+		groups[index].Count++
 	}
 
-	if event.JudgePercentage == 0 {
-		final = jammers
-		return
+	totalWeight := 0.0
+	for i := range groups {
+		group := &groups[i]
+		if group.Count == 0 {
+			continue
+		}
+		group.Average.Scale(1 / float64(group.Count))
+		totalWeight += group.Weight
 	}
 
-	p := event.JudgePercentage / 100
-	jammerPart := jammers
-	jammerPart.Scale(1 - p)
-	final.Add(&jammerPart)
+	if totalWeight <= 0 {
+		return final, groups
+	}
 
-	judgesPart := judges
-	judgesPart.Scale(p)
-	final.Add(&judgesPart)
+	for _, group := range groups {
+		if group.Count == 0 {
+			continue
+		}
+		part := group.Average
+		part.Scale(group.Weight / totalWeight)
+		final.Add(&part)
+	}
 
-	return final, jammers, judges
+	return final, groups
 }
 
 // Aspects contains criteria for scoring a game.
@@ -142,7 +242,16 @@ type AspectsInfo struct {
 type AspectInfo struct {
 	Scores       []float64
 	MemberScores []float64
-	Comments     []string
+	Comments     []Comment
+}
+
+// Comment is a single comment from a ballot.
+type Comment struct {
+	// Ref is the Ballot.Ref of the comment.
+	Ref  string
+	Text string
+	// Feedback is the team response, if any.
+	Feedback *Feedback
 }
 
 // String pretty prints an aspect.
@@ -198,25 +307,25 @@ func (aspects *Aspects) Scale(multiplier float64) {
 	aspects.Overall.Score *= multiplier
 }
 
-// Add includes other into aspects.
-func (aspects *AspectsInfo) Add(other *Aspects, isMember bool) {
-	aspects.Theme.Add(&other.Theme, isMember)
-	aspects.Enjoyment.Add(&other.Enjoyment, isMember)
-	aspects.Aesthetics.Add(&other.Aesthetics, isMember)
-	aspects.Innovation.Add(&other.Innovation, isMember)
-	aspects.Bonus.Add(&other.Bonus, isMember)
-	aspects.Overall.Add(&other.Overall, isMember)
+// Add includes other into aspects, ref identifies the ballot of comments.
+func (aspects *AspectsInfo) Add(other *Aspects, ref string, isMember bool) {
+	aspects.Theme.Add(&other.Theme, ref, isMember)
+	aspects.Enjoyment.Add(&other.Enjoyment, ref, isMember)
+	aspects.Aesthetics.Add(&other.Aesthetics, ref, isMember)
+	aspects.Innovation.Add(&other.Innovation, ref, isMember)
+	aspects.Bonus.Add(&other.Bonus, ref, isMember)
+	aspects.Overall.Add(&other.Overall, ref, isMember)
 }
 
 // Add includes other into aspect.
-func (aspect *AspectInfo) Add(other *Aspect, isMember bool) {
+func (aspect *AspectInfo) Add(other *Aspect, ref string, isMember bool) {
 	if isMember {
 		aspect.MemberScores = append(aspect.MemberScores, other.Score)
 	} else {
 		aspect.Scores = append(aspect.Scores, other.Score)
 	}
 	if other.Comment != "" {
-		aspect.Comments = append(aspect.Comments, other.Comment)
+		aspect.Comments = append(aspect.Comments, Comment{Ref: ref, Text: other.Comment})
 	}
 }
 
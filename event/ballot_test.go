@@ -0,0 +1,109 @@
+package event
+
+import (
+	"math"
+	"testing"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+func completedBallot(voter user.UserID, overall float64) *Ballot {
+	return &Ballot{
+		Voter:     voter,
+		Completed: true,
+		Aspects:   Aspects{Overall: Aspect{Score: overall}},
+	}
+}
+
+func TestAverageScores(t *testing.T) {
+	judges := VoterGroup{Name: "Judges", Weight: 40, Members: []user.UserID{10, 11}}
+	guests := VoterGroup{Name: "Guests", Weight: 0, Members: []user.UserID{20}}
+	event := &Event{Groups: []VoterGroup{judges, guests}}
+
+	tests := []struct {
+		name     string
+		ballots  []*Ballot
+		overall  float64
+		counts   map[string]int
+		unscored bool
+	}{
+		{
+			name:    "only jammers",
+			ballots: []*Ballot{completedBallot(1, 3), completedBallot(2, 5)},
+			overall: 4,
+			counts:  map[string]int{DefaultGroupName: 2},
+		},
+		{
+			name:    "weighted groups",
+			ballots: []*Ballot{completedBallot(1, 2), completedBallot(10, 4), completedBallot(11, 5)},
+			overall: 0.6*2 + 0.4*4.5,
+			counts:  map[string]int{DefaultGroupName: 1, "Judges": 2},
+		},
+		{
+			name:    "rescaled without jammers",
+			ballots: []*Ballot{completedBallot(10, 4)},
+			overall: 4,
+			counts:  map[string]int{"Judges": 1},
+		},
+		{
+			name:    "zero weight group ignored",
+			ballots: []*Ballot{completedBallot(1, 3), completedBallot(20, 1)},
+			overall: 3,
+			counts:  map[string]int{DefaultGroupName: 1, "Guests": 1},
+		},
+		{
+			name:     "only zero weight group",
+			ballots:  []*Ballot{completedBallot(20, 5)},
+			overall:  0,
+			counts:   map[string]int{"Guests": 1},
+			unscored: true,
+		},
+		{
+			name:    "incomplete ballots ignored",
+			ballots: []*Ballot{completedBallot(1, 3), {Voter: 2, Aspects: Aspects{Overall: Aspect{Score: 1}}}},
+			overall: 3,
+			counts:  map[string]int{DefaultGroupName: 1},
+		},
+		{
+			name:    "no ballots",
+			overall: 0,
+			counts:  map[string]int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := &TeamResult{Ballots: test.ballots}
+			result.Average, result.GroupAverages = AverageScores(test.ballots, event)
+
+			if math.Abs(result.Average.Overall.Score-test.overall) > scoreEpsilon {
+				t.Errorf("overall: got %v, expected %v", result.Average.Overall.Score, test.overall)
+			}
+			if len(result.GroupAverages) != 3 || result.GroupAverages[0].Name != DefaultGroupName {
+				t.Fatalf("unexpected groups %+v", result.GroupAverages)
+			}
+			if weight := result.GroupAverages[0].Weight; weight != 60 {
+				t.Errorf("default group weight: got %v, expected 60", weight)
+			}
+			for _, group := range result.GroupAverages {
+				if group.Count != test.counts[group.Name] {
+					t.Errorf("%v count: got %v, expected %v", group.Name, group.Count, test.counts[group.Name])
+				}
+			}
+			if result.Unscored() != test.unscored {
+				t.Errorf("unscored: got %v, expected %v", result.Unscored(), test.unscored)
+			}
+		})
+	}
+}
+
+func TestAverageScoresLegacyJudges(t *testing.T) {
+	event := &Event{JudgePercentage: 50, Judges: []user.UserID{10}}
+	final, groups := AverageScores([]*Ballot{completedBallot(1, 2), completedBallot(10, 4)}, event)
+	if final.Overall.Score != 3 {
+		t.Errorf("overall: got %v, expected 3", final.Overall.Score)
+	}
+	if len(groups) != 2 || groups[1].Name != legacyJudgesName || groups[1].Count != 1 {
+		t.Errorf("unexpected groups %+v", groups)
+	}
+}
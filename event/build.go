@@ -0,0 +1,384 @@
+package event
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adinfinit/jamvote/blob"
+	"github.com/adinfinit/jamvote/user"
+)
+
+const (
+	// MaxBuildUpload is the maximum size of an uploaded build archive.
+	MaxBuildUpload = 200 << 20
+	// MaxBuildSize is the maximum total size of extracted build files.
+	MaxBuildSize = 500 << 20
+	// MaxBuildFileSize is the maximum size of a single extracted build file.
+	MaxBuildFileSize = 64 << 20
+	// MaxBuildFiles is the maximum number of files in a build.
+	MaxBuildFiles = 2000
+	// BuildEntry is the page that starts the game.
+	BuildEntry = "index.html"
+)
+
+// Build is a hosted HTML5 build of a game.
+//
+// Every upload gets a new version, which replaces the previous one.
+type Build struct {
+	// Version is incremented on every upload.
+	Version int
+	// Files is zero when no build has been uploaded or it was removed.
+	Files      int
+	Size       int64
+	Uploaded   time.Time
+	UploadedBy user.UserID
+}
+
+// Empty returns whether no build has been uploaded.
+func (build Build) Empty() bool { return build.Files == 0 }
+
+// SizeMB returns the extracted size in megabytes.
+func (build Build) SizeMB() string {
+	return strconv.FormatFloat(float64(build.Size)/(1<<20), 'f', 1, 64)
+}
+
+// PlayPath returns the location of the hosted build of the team.
+func (team *Team) PlayPath() string {
+	return fmt.Sprintf("/play/%v/%v/%v/%v", team.EventID, team.ID, team.Game.Build.Version, BuildEntry)
+}
+
+// buildPrefix returns the blob key prefix of a build version.
+func buildPrefix(eventid EventID, teamid TeamID, version int) string {
+	return fmt.Sprintf("builds/%v/%v/%v", eventid, teamid, version)
+}
+
+// buildManifest returns the blob key listing the files of a build version.
+//
+// The manifest is outside of the version directory, so it is not served.
+func buildManifest(eventid EventID, teamid TeamID, version int) string {
+	return buildPrefix(eventid, teamid, version) + ".manifest"
+}
+
+// buildFile is a file to extract from an uploaded archive.
+type buildFile struct {
+	name string
+	file *zip.File
+}
+
+// buildFiles validates the archive and returns the files to extract.
+//
+// When all files are inside a single directory, that directory is
+// used as the root of the build.
+func buildFiles(archive *zip.Reader) ([]buildFile, error) {
+	files := []buildFile{}
+	var total uint64
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name := file.Name
+		if strings.HasPrefix(name, "__MACOSX/") || path.Base(name) == ".DS_Store" {
+			continue
+		}
+		if !blob.ValidKey(name) {
+			return nil, fmt.Errorf("archive contains invalid file name %q", name)
+		}
+		if file.UncompressedSize64 > MaxBuildFileSize {
+			return nil, fmt.Errorf("%v can be at most %vMB when extracted", name, MaxBuildFileSize>>20)
+		}
+		total += file.UncompressedSize64
+		if total > MaxBuildSize {
+			return nil, fmt.Errorf("build can be at most %vMB when extracted", MaxBuildSize>>20)
+		}
+		files = append(files, buildFile{name: name, file: file})
+	}
+	if len(files) == 0 {
+		return nil, errors.New("archive is empty")
+	}
+	if len(files) > MaxBuildFiles {
+		return nil, fmt.Errorf("build can have at most %v files", MaxBuildFiles)
+	}
+
+	if dir, _, ok := strings.Cut(files[0].name, "/"); ok {
+		prefix := dir + "/"
+		shared := true
+		for _, file := range files {
+			shared = shared && strings.HasPrefix(file.name, prefix)
+		}
+		if shared {
+			for i := range files {
+				files[i].name = strings.TrimPrefix(files[i].name, prefix)
+			}
+		}
+	}
+
+	for _, file := range files {
+		if file.name == BuildEntry {
+			return files, nil
+		}
+	}
+	return nil, fmt.Errorf("archive must contain %v at the top level", BuildEntry)
+}
+
+// storeBuild extracts files as a new build version of the team.
+func (server *Server) storeBuild(context *Context, files []buildFile) (Build, error) {
+	team := context.Team
+	build := Build{
+		Version:    team.Game.Build.Version + 1,
+		Files:      len(files),
+		Uploaded:   time.Now().UTC(),
+		UploadedBy: context.CurrentUser.ID,
+	}
+	prefix := buildPrefix(team.EventID, team.ID, build.Version)
+
+	names := []string{}
+	for _, file := range files {
+		data, err := readBuildFile(file.file, min(MaxBuildFileSize, MaxBuildSize-build.Size))
+		if err != nil {
+			server.deleteBuildFiles(context, prefix, names)
+			return Build{}, fmt.Errorf("%v: %w", file.name, err)
+		}
+		if err := server.Blobs.Put(context, prefix+"/"+file.name, data); err != nil {
+			server.deleteBuildFiles(context, prefix, names)
+			return Build{}, fmt.Errorf("unable to store %v: %w", file.name, err)
+		}
+		build.Size += int64(len(data))
+		names = append(names, file.name)
+	}
+
+	manifest := []byte(strings.Join(names, "\n"))
+	if err := server.Blobs.Put(context, buildManifest(team.EventID, team.ID, build.Version), manifest); err != nil {
+		server.deleteBuildFiles(context, prefix, names)
+		return Build{}, fmt.Errorf("unable to store manifest: %w", err)
+	}
+	return build, nil
+}
+
+// readBuildFile reads a file from the archive, failing when it is larger than limit.
+//
+// The declared sizes in the archive cannot be trusted, so limit is
+// enforced while reading.
+func readBuildFile(file *zip.File, limit int64) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("file is larger than declared or the build is larger than %vMB when extracted", MaxBuildSize>>20)
+	}
+	return data, nil
+}
+
+// deleteBuild removes all files of a build version, failures are only logged.
+func (server *Server) deleteBuild(context *Context, team *Team, version int) {
+	manifest := buildManifest(team.EventID, team.ID, version)
+	data, err := server.Blobs.Get(context, manifest)
+	if err != nil {
+		context.Site.Log.Error("failed to read build manifest", "key", manifest, "error", err)
+		return
+	}
+	names := strings.Split(string(data), "\n")
+	server.deleteBuildFiles(context, buildPrefix(team.EventID, team.ID, version), names)
+	if err := server.Blobs.Delete(context, manifest); err != nil {
+		context.Site.Log.Error("failed to delete build manifest", "key", manifest, "error", err)
+	}
+}
+
+// deleteBuildFiles removes extracted files, failures are only logged.
+func (server *Server) deleteBuildFiles(context *Context, prefix string, names []string) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if err := server.Blobs.Delete(context, prefix+"/"+name); err != nil {
+			context.Site.Log.Error("failed to delete build file", "key", prefix+"/"+name, "error", err)
+		}
+	}
+}
+
+// TeamBuild handles uploading and removing the hosted build of a game.
+func (server *Server) TeamBuild(context *Context) {
+	if !server.canEditTeam(context) {
+		return
+	}
+	if server.Blobs == nil {
+		context.FlashError("Build uploads have not been configured.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	context.Data["MaxBuildUploadMB"] = MaxBuildUpload >> 20
+	context.Data["MaxBuildSizeMB"] = MaxBuildSize >> 20
+	context.Data["MaxBuildFiles"] = MaxBuildFiles
+	if context.Request.Method != http.MethodPost {
+		context.Render("event-team-build")
+		return
+	}
+
+	back := context.Event.Path("team", context.Team.ID, "build")
+	context.Request.Body = http.MaxBytesReader(context.Response, context.Request.Body, MaxBuildUpload+(1<<20))
+	if err := context.Request.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			context.FlashError(fmt.Sprintf("Build archive can be at most %vMB.", MaxBuildUpload>>20))
+		} else {
+			context.FlashError(fmt.Sprintf("Invalid upload: %v", err))
+		}
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+	defer context.Request.MultipartForm.RemoveAll()
+
+	team := context.Team
+	previous := team.Game.Build
+
+	var message string
+	switch context.FormValue("action") {
+	case "upload":
+		uploads := context.Request.MultipartForm.File["Build"]
+		if len(uploads) != 1 {
+			context.FlashError("Select a zip archive to upload.")
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		file, err := uploads[0].Open()
+		if err != nil {
+			context.FlashError(fmt.Sprintf("Unable to read upload: %v", err))
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		defer file.Close()
+
+		archive, err := zip.NewReader(file, uploads[0].Size)
+		if err != nil {
+			context.FlashError("Build must be a zip archive.")
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		files, err := buildFiles(archive)
+		if err != nil {
+			context.FlashError(err.Error())
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		build, err := server.storeBuild(context, files)
+		if err != nil {
+			context.FlashError(err.Error())
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		team.Game.Build = build
+		message = fmt.Sprintf("Build version %v is live.", build.Version)
+	case "remove":
+		if previous.Empty() {
+			context.FlashError("There is no build to remove.")
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		// keep the version, so that the next upload doesn't reuse cached paths
+		team.Game.Build = Build{Version: previous.Version}
+		message = "Build removed."
+	default:
+		context.FlashError("Unknown build action.")
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+
+	if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+		context.FlashError(fmt.Sprintf("Unable to update team: %v", err))
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+	if !previous.Empty() {
+		server.deleteBuild(context, team, previous.Version)
+	}
+
+	context.FlashMessage(message)
+	context.Redirect(back, http.StatusSeeOther)
+}
+
+// buildEncodings are precompressed file extensions produced by game engines.
+var buildEncodings = map[string]string{
+	".gz": "gzip",
+	".br": "br",
+}
+
+// PlayBuild serves files of hosted builds.
+//
+// Builds are untrusted content, hence they are served in a sandbox
+// without access to the site origin, cookies or forms.
+func (server *Server) PlayBuild(w http.ResponseWriter, r *http.Request) {
+	eventid := EventID(r.PathValue("eventid"))
+	teamid, errTeam := strconv.ParseInt(r.PathValue("teamid"), 10, 64)
+	version, errVersion := strconv.Atoi(r.PathValue("version"))
+	name := r.PathValue("file")
+	if !eventid.Valid() || errTeam != nil || errVersion != nil || version <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+	if name == "" {
+		http.Redirect(w, r, r.URL.Path+BuildEntry, http.StatusSeeOther)
+		return
+	}
+	if server.Blobs == nil || !blob.ValidKey(name) {
+		http.NotFound(w, r)
+		return
+	}
+	if domain, ok := server.playDomain(); ok && !strings.EqualFold(r.Host, domain.Host) {
+		http.Redirect(w, r, domain.JoinPath(r.URL.Path).String(), http.StatusSeeOther)
+		return
+	}
+
+	key := buildPrefix(eventid, TeamID(teamid), version) + "/" + name
+	data, err := server.Blobs.Get(r.Context(), key)
+	if err == blob.ErrNotExists {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		server.Site.Log.Error("failed to get build file", "key", key, "error", err)
+		http.Error(w, "Unable to get file.", http.StatusInternalServerError)
+		return
+	}
+
+	header := w.Header()
+	contentType := blob.ContentType(name)
+	if encoding, ok := buildEncodings[path.Ext(name)]; ok {
+		header.Set("Content-Encoding", encoding)
+		contentType = blob.ContentType(strings.TrimSuffix(name, path.Ext(name)))
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Security-Policy", "sandbox allow-scripts allow-pointer-lock allow-popups")
+	header.Set("Access-Control-Allow-Origin", "*")
+	header.Set("Cross-Origin-Resource-Policy", "cross-origin")
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", "public, max-age=3600")
+	_, _ = w.Write(data)
+}
+
+// playDomain returns the parsed PlayDomain, when it is configured.
+func (server *Server) playDomain() (*url.URL, bool) {
+	if server.PlayDomain == "" {
+		return nil, false
+	}
+	domain, err := url.Parse(server.PlayDomain)
+	if err != nil || domain.Host == "" {
+		server.Site.Log.Error("invalid play domain", "domain", server.PlayDomain, "error", err)
+		return nil, false
+	}
+	return domain, true
+}
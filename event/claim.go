@@ -0,0 +1,321 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// DefaultClaimDays is the default number of days a claim link is valid.
+const DefaultClaimDays = 14
+
+// MemberClaim is a one-time link for binding an unregistered member to an account.
+type MemberClaim struct {
+	Token string
+	// Name is the name of the unregistered member.
+	Name      string
+	CreatedBy user.UserID
+	Created   time.Time
+	Expires   time.Time
+}
+
+// Expired returns whether the claim can no longer be used.
+func (claim *MemberClaim) Expired() bool {
+	return time.Now().After(claim.Expires)
+}
+
+// ErrClaimExpired is returned when claiming with an expired link.
+var ErrClaimExpired = errors.New("claim link has expired")
+
+// unregisteredMember finds the index of an unregistered member by name.
+func (team *Team) unregisteredMember(name string) (int, bool) {
+	for i, member := range team.Members {
+		if member.ID == 0 && strings.EqualFold(member.Name, name) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// ClaimByToken finds a claim by its token.
+func (team *Team) ClaimByToken(token string) (*MemberClaim, bool) {
+	for i := range team.Claims {
+		if team.Claims[i].Token == token {
+			return &team.Claims[i], true
+		}
+	}
+	return nil, false
+}
+
+// AddClaim creates a claim link for the unregistered member name.
+//
+// An existing claim for the same member is replaced.
+func (team *Team) AddClaim(name string, createdBy user.UserID, valid time.Duration) (*MemberClaim, error) {
+	index, ok := team.unregisteredMember(name)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an unregistered member of %v", name, team.Name)
+	}
+	name = team.Members[index].Name
+
+	team.Claims = slices.DeleteFunc(team.Claims, func(claim MemberClaim) bool {
+		return strings.EqualFold(claim.Name, name)
+	})
+
+	now := time.Now().UTC()
+	team.Claims = append(team.Claims, MemberClaim{
+		Token:     newToken(),
+		Name:      name,
+		CreatedBy: createdBy,
+		Created:   now,
+		Expires:   now.Add(valid),
+	})
+	return &team.Claims[len(team.Claims)-1], nil
+}
+
+// RemoveClaim removes the claim with token.
+func (team *Team) RemoveClaim(token string) bool {
+	count := len(team.Claims)
+	team.Claims = slices.DeleteFunc(team.Claims, func(claim MemberClaim) bool {
+		return claim.Token == token
+	})
+	return len(team.Claims) != count
+}
+
+// Claim binds the member of the claim with token to u and removes the claim.
+func (team *Team) Claim(token string, u *user.User) error {
+	claim, ok := team.ClaimByToken(token)
+	if !ok {
+		return errors.New("claim link does not exist or has already been used")
+	}
+	if claim.Expired() {
+		return ErrClaimExpired
+	}
+	if team.HasMember(u) {
+		return fmt.Errorf("you are already a member of %v", team.Name)
+	}
+	index, ok := team.unregisteredMember(claim.Name)
+	if !ok {
+		return fmt.Errorf("%q is no longer an unregistered member of %v", claim.Name, team.Name)
+	}
+
+	team.Members[index] = Member{ID: u.ID, Name: u.Name}
+	team.RemoveClaim(token)
+	team.Invites = slices.DeleteFunc(team.Invites, func(invite Invite) bool {
+		return invite.User == u.ID
+	})
+	team.RemoveJoinRequest(u.ID)
+	return nil
+}
+
+// claimURL returns the absolute link for claim.
+func (server *Server) claimURL(context *Context, team *Team, claim *MemberClaim) string {
+	return server.absoluteURL(context.Event.Path("team", team.ID, "claim", claim.Token))
+}
+
+// Claims lists outstanding claim links and handles creating and revoking them.
+func (server *Server) Claims(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to manage claim links.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get teams: %v", err))
+	}
+	sort.Slice(teams, func(i, k int) bool {
+		return teams[i].Less(teams[k])
+	})
+
+	if context.Request.Method == http.MethodPost {
+		server.updateClaims(context, teams)
+		context.Redirect(context.Event.Path("claims"), http.StatusSeeOther)
+		return
+	}
+
+	type ClaimLink struct {
+		Team  *Team
+		Claim *MemberClaim
+		URL   string
+	}
+
+	claims := []ClaimLink{}
+	unclaimed := 0
+	for _, team := range teams {
+		for i := range team.Claims {
+			claim := &team.Claims[i]
+			claims = append(claims, ClaimLink{
+				Team:  team,
+				Claim: claim,
+				URL:   server.claimURL(context, team, claim),
+			})
+		}
+		for _, member := range team.Members {
+			if member.ID != 0 {
+				continue
+			}
+			if !slices.ContainsFunc(team.Claims, func(claim MemberClaim) bool {
+				return strings.EqualFold(claim.Name, member.Name)
+			}) {
+				unclaimed++
+			}
+		}
+	}
+
+	context.Data["Claims"] = claims
+	context.Data["Unclaimed"] = unclaimed
+	context.Data["DefaultClaimDays"] = DefaultClaimDays
+	context.Render("event-claims")
+}
+
+// updateClaims creates or revokes claim links based on the posted form.
+func (server *Server) updateClaims(context *Context, teams []*Team) {
+	days := DefaultClaimDays
+	if value := context.FormValue("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			context.FlashError("Claim links must be valid for at least 1 day.")
+			return
+		}
+		days = parsed
+	}
+	valid := time.Duration(days) * 24 * time.Hour
+
+	findTeam := func() (*Team, bool) {
+		id, err := strconv.ParseInt(context.FormValue("team"), 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		for _, team := range teams {
+			if team.ID == TeamID(id) {
+				return team, true
+			}
+		}
+		return nil, false
+	}
+
+	changed := []*Team{}
+	switch context.FormValue("action") {
+	case "create":
+		team, ok := findTeam()
+		if !ok {
+			context.FlashError("Team does not exist.")
+			return
+		}
+		if _, err := team.AddClaim(context.FormValue("member"), context.CurrentUser.ID, valid); err != nil {
+			context.FlashError(err.Error())
+			return
+		}
+		changed = append(changed, team)
+	case "create-all":
+		for _, team := range teams {
+			created := false
+			for _, member := range team.Members {
+				if member.ID != 0 {
+					continue
+				}
+				if slices.ContainsFunc(team.Claims, func(claim MemberClaim) bool {
+					return strings.EqualFold(claim.Name, member.Name) && !claim.Expired()
+				}) {
+					continue
+				}
+				if _, err := team.AddClaim(member.Name, context.CurrentUser.ID, valid); err == nil {
+					created = true
+				}
+			}
+			if created {
+				changed = append(changed, team)
+			}
+		}
+	case "revoke":
+		team, ok := findTeam()
+		if !ok || !team.RemoveClaim(context.FormValue("token")) {
+			context.FlashError("Claim link does not exist.")
+			return
+		}
+		changed = append(changed, team)
+	default:
+		context.FlashError("Unknown claim action.")
+		return
+	}
+
+	for _, team := range changed {
+		if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+			context.FlashError(fmt.Sprintf("Unable to update team: %v", err))
+			return
+		}
+	}
+	context.FlashMessage(fmt.Sprintf("Updated claim links of %v teams.", len(changed)))
+}
+
+// ClaimMember lets a signed in user claim an unregistered member slot.
+func (server *Server) ClaimMember(context *Context) {
+	if context.Team == nil {
+		teamid, _ := context.IntParam("teamid")
+		context.FlashError(fmt.Sprintf("Team %v does not exist.", teamid))
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+	if context.CurrentUser == nil {
+		context.FlashError("You must be logged in to claim a team membership.")
+		context.Redirect("/user/login", http.StatusSeeOther)
+		return
+	}
+
+	token, _ := context.StringParam("token")
+	claim, ok := context.Team.ClaimByToken(token)
+	if !ok {
+		context.FlashError("Claim link does not exist or has already been used.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+	if claim.Expired() {
+		context.FlashError("Claim link has expired, ask an organizer for a new one.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	context.Data["Claim"] = claim
+	if context.Request.Method != http.MethodPost {
+		context.Render("event-team-claim")
+		return
+	}
+
+	team := context.Team
+	if err := server.canJoinTeam(context, team, context.CurrentUser); err != nil {
+		context.FlashErrorNow(err.Error())
+		context.Response.WriteHeader(http.StatusBadRequest)
+		context.Render("event-team-claim")
+		return
+	}
+	if err := team.Claim(token, context.CurrentUser); err != nil {
+		context.FlashErrorNow(err.Error())
+		context.Response.WriteHeader(http.StatusBadRequest)
+		context.Render("event-team-claim")
+		return
+	}
+	if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to update team: %v", err))
+		context.Response.WriteHeader(http.StatusInternalServerError)
+		context.Render("event-team-claim")
+		return
+	}
+
+	if !context.Event.HasJammer(context.CurrentUser) {
+		context.Event.AddRemoveJammers([]user.UserID{context.CurrentUser.ID}, nil)
+		if err := context.Events.Update(context.Event); err != nil {
+			context.FlashError(fmt.Sprintf("Unable to approve as jammer: %v", err))
+		}
+	}
+
+	context.FlashMessage(fmt.Sprintf("You are now a member of %v.", team.Name))
+	context.Redirect(context.Event.Path("team", team.ID), http.StatusSeeOther)
+}
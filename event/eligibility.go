@@ -0,0 +1,85 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+var (
+	// ErrOwnTeam is returned when voting for your own team.
+	ErrOwnTeam = errors.New("you cannot vote for your own team")
+	// ErrConflict is returned when the voter has a conflict of interest with the team.
+	ErrConflict = errors.New("you have a conflict of interest with this team")
+	// ErrNotSubmitted is returned when the game has not been submitted.
+	ErrNotSubmitted = errors.New("this game has not been submitted yet")
+	// ErrNoncompeting is returned when the event does not allow voting for noncompeting games.
+	ErrNoncompeting = errors.New("this game is not competing")
+	// ErrNotAssigned is returned when the event requires games to be assigned before voting.
+	ErrNotAssigned = errors.New("this game has not been assigned to you")
+)
+
+// CheckAssignable returns why voter cannot be assigned team, nil when allowed.
+func (event *Event) CheckAssignable(voter user.UserID, team *Team) error {
+	if team.HasMemberID(voter) {
+		return ErrOwnTeam
+	}
+	if team.HasConflict(voter) {
+		return ErrConflict
+	}
+	if !team.HasSubmitted(event.Submission) {
+		return ErrNotSubmitted
+	}
+	if team.Game.Noncompeting && event.SkipNoncompeting {
+		return ErrNoncompeting
+	}
+	return nil
+}
+
+// CheckVotable returns why voter cannot vote for team, nil when allowed.
+//
+// hasBallot reports whether the voter already has a ballot for the team,
+// either assigned by queue filling or submitted before.
+func (event *Event) CheckVotable(voter user.UserID, team *Team, hasBallot bool) error {
+	if err := event.CheckAssignable(voter, team); err != nil {
+		return err
+	}
+	if event.RequireAssignment && !hasBallot {
+		return ErrNotAssigned
+	}
+	return nil
+}
+
+// HasConflict checks whether userid has a conflict of interest with the team.
+func (team *Team) HasConflict(userid user.UserID) bool {
+	return slices.Contains(team.Conflicts, userid)
+}
+
+// conflictNames returns names of users with a conflict of interest.
+func (team *Team) conflictNames(users []*user.User) string {
+	names := []string{}
+	for _, userid := range team.Conflicts {
+		if u, ok := findUserByID(users, userid); ok {
+			names = append(names, u.Name)
+		}
+	}
+	return strings.Join(names, "\n")
+}
+
+// parseConflicts finds users listed in text.
+func parseConflicts(text string, users []*user.User) ([]user.UserID, error) {
+	conflicts := []user.UserID{}
+	for _, name := range parseWordList(text) {
+		u, ok := findUserByName(users, name)
+		if !ok {
+			return nil, fmt.Errorf("conflict user %q does not exist", name)
+		}
+		if !slices.Contains(conflicts, u.ID) {
+			conflicts = append(conflicts, u.ID)
+		}
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,121 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+func TestEligibility(t *testing.T) {
+	const (
+		member     user.UserID = 1
+		conflicted user.UserID = 2
+		voter      user.UserID = 3
+	)
+
+	submitted := func() *Team {
+		team := &Team{
+			Name:      "Team",
+			Members:   []Member{{ID: member, Name: "Member"}},
+			Conflicts: []user.UserID{conflicted},
+		}
+		team.Game.Name = "Game"
+		team.Game.Links = []Link{{Type: LinkDownload, URL: "https://example.com/game.zip"}}
+		return team
+	}
+	unsubmitted := func() *Team {
+		team := submitted()
+		team.Game.Links = nil
+		return team
+	}
+	noncompeting := func() *Team {
+		team := submitted()
+		team.Game.Noncompeting = true
+		return team
+	}
+
+	tests := []struct {
+		name      string
+		event     Event
+		voter     user.UserID
+		team      *Team
+		hasBallot bool
+		assign    error
+		vote      error
+	}{
+		{"allowed", Event{}, voter, submitted(), false, nil, nil},
+		{"own team", Event{}, member, submitted(), false, ErrOwnTeam, ErrOwnTeam},
+		{"own team with ballot", Event{}, member, submitted(), true, ErrOwnTeam, ErrOwnTeam},
+		{"conflict", Event{}, conflicted, submitted(), false, ErrConflict, ErrConflict},
+		{"conflict with ballot", Event{}, conflicted, submitted(), true, ErrConflict, ErrConflict},
+		{"not submitted", Event{}, voter, unsubmitted(), false, ErrNotSubmitted, ErrNotSubmitted},
+		{"link type not accepted", Event{Submission: SubmissionRules{LinkTypes: []LinkType{LinkItch}}}, voter, submitted(), false, ErrNotSubmitted, ErrNotSubmitted},
+		{"noncompeting allowed", Event{}, voter, noncompeting(), false, nil, nil},
+		{"noncompeting skipped", Event{SkipNoncompeting: true}, voter, noncompeting(), false, ErrNoncompeting, ErrNoncompeting},
+		{"competing with skip", Event{SkipNoncompeting: true}, voter, submitted(), false, nil, nil},
+		{"assignment required", Event{RequireAssignment: true}, voter, submitted(), false, nil, ErrNotAssigned},
+		{"assignment required with ballot", Event{RequireAssignment: true}, voter, submitted(), true, nil, nil},
+		{"own team before assignment", Event{RequireAssignment: true}, member, submitted(), false, ErrOwnTeam, ErrOwnTeam},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.event.CheckAssignable(test.voter, test.team); err != test.assign {
+				t.Errorf("CheckAssignable: got %v, want %v", err, test.assign)
+			}
+			if err := test.event.CheckVotable(test.voter, test.team, test.hasBallot); err != test.vote {
+				t.Errorf("CheckVotable: got %v, want %v", err, test.vote)
+			}
+		})
+	}
+}
+
+func TestCheckProxyBallot(t *testing.T) {
+	const (
+		member      user.UserID = 1
+		voter       user.UserID = 3
+		placeholder user.UserID = -1
+	)
+	team := &Team{Name: "Team", Members: []Member{{ID: member, Name: "Member"}}}
+	team.Game.Name = "Game"
+	team.Game.Links = []Link{{Type: LinkDownload, URL: "https://example.com/game.zip"}}
+
+	event := &Event{RequireAssignment: true}
+	tests := []struct {
+		name     string
+		voter    user.UserID
+		existing *Ballot
+		err      error
+	}{
+		{"unassigned voter", voter, nil, nil},
+		{"placeholder judge", placeholder, nil, nil},
+		{"own team", member, nil, ErrOwnTeam},
+		{"replaces proxy ballot", voter, &Ballot{Completed: true, Proxy: true}, nil},
+		{"keeps voter ballot", voter, &Ballot{Completed: true}, ErrBallotByVoter},
+		{"fills queued ballot", voter, &Ballot{}, nil},
+	}
+	for _, test := range tests {
+		if err := event.checkProxyBallot(test.voter, team, test.existing); err != test.err {
+			t.Errorf("%v: got %v, want %v", test.name, err, test.err)
+		}
+	}
+}
+
+func TestParseConflicts(t *testing.T) {
+	users := []*user.User{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+	}
+
+	conflicts, err := parseConflicts("alice\nBob, Alice", users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 2 || conflicts[0] != 1 || conflicts[1] != 2 {
+		t.Errorf("got %v, want [1 2]", conflicts)
+	}
+
+	if _, err := parseConflicts("Charlie", users); err == nil {
+		t.Error("expected error for unknown user")
+	}
+}
@@ -2,7 +2,9 @@ package event
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/gob"
+	"encoding/hex"
 	"errors"
 	"slices"
 	"time"
@@ -25,6 +27,10 @@ type Repo interface {
 
 	TeamRepo
 	BallotRepo
+	ModerationRepo
+	FeedbackRepo
+	SeekerRepo
+	LinkStatusRepo
 }
 
 // ErrNotExists is returned when an event doesn't exist.
@@ -72,6 +78,7 @@ type Event struct {
 	// EndTime is the end time of the event
 	EndTime time.Time `datastore:",noindex"`
 
+	// JudgePercentage is the weight of Judges from before voter groups.
 	JudgePercentage float64 `datastore:",noindex"`
 
 	// New Registration is allowed
@@ -88,7 +95,38 @@ type Event struct {
 
 	Organizers []user.UserID `datastore:",noindex"`
 	Jammers    []user.UserID `datastore:",noindex"`
-	Judges     []user.UserID `datastore:",noindex"`
+	// Judges are voters weighted by JudgePercentage from before voter groups.
+	Judges []user.UserID `datastore:",noindex"`
+
+	// Groups are weighted voter groups, everyone else belongs to DefaultGroupName.
+	Groups []VoterGroup `datastore:",noindex"`
+
+	// SkipNoncompeting disallows voting for noncompeting games.
+	SkipNoncompeting bool `datastore:",noindex"`
+	// RequireAssignment allows voting only for games assigned to the voter.
+	RequireAssignment bool `datastore:",noindex"`
+
+	// TieBreakers order teams with equal overall scores.
+	TieBreakers []TieBreaker `datastore:",noindex"`
+
+	// Placeholders are judges without accounts, whose ballots are entered by admins.
+	Placeholders []PlaceholderJudge `datastore:",noindex"`
+
+	// TeamRules are limits on team size and membership.
+	TeamRules TeamRules `datastore:",noindex"`
+	// Submission decides which links count as a submitted game.
+	Submission SubmissionRules `datastore:",noindex"`
+
+	// Kiosk contains the showcase kiosk settings.
+	Kiosk Kiosk `datastore:",noindex"`
+	// Schedule is the presentation order of team demos.
+	Schedule Schedule `datastore:",noindex"`
+
+	// Notifications are messages for organizers.
+	Notifications []Notification `datastore:",noindex"`
+
+	// FlaggedWords are words that automatically flag comments for moderation.
+	FlaggedWords []string `datastore:",noindex"`
 }
 
 func init() {
@@ -138,22 +176,6 @@ func (event *Event) AddRemoveJammers(added, removed []user.UserID) {
 	event.Jammers = result
 }
 
-// AddRemoveJudges adds and removes judges from the event.
-func (event *Event) AddRemoveJudges(added, removed []user.UserID) {
-	result := []user.UserID{}
-	for _, userid := range event.Judges {
-		if !containsUser(removed, userid) {
-			result = append(result, userid)
-		}
-	}
-	for _, userid := range added {
-		if !containsUser(result, userid) {
-			result = append(result, userid)
-		}
-	}
-	event.Judges = result
-}
-
 // Less compares events based on start time.
 func (event *Event) Less(other *Event) bool {
 	return event.startTime().After(other.startTime())
@@ -167,22 +189,9 @@ func (event *Event) startTime() time.Time {
 	return event.Created
 }
 
-func (event *Event) HasJudgeById(userId *user.UserID) bool {
-	if userId == nil {
-		return false
-	}
-
-	return containsUser(event.Judges, *userId)
-}
-
-func (event *Event) HasJudge(u *user.User) bool {
-	if u == nil {
-		return false
-	}
-
-	return containsUser(event.Judges, u.ID)
-}
-
-func (event *Event) JudgesExist() bool {
-	return event.JudgePercentage > 0 && len(event.Judges) != 0
+// newToken returns a new random token, which is hard to guess.
+func newToken() string {
+	var data [12]byte
+	_, _ = rand.Read(data[:])
+	return hex.EncodeToString(data[:])
 }
@@ -0,0 +1,225 @@
+package event
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// FeedbackRepo is used to manage team responses to ballot comments.
+type FeedbackRepo interface {
+	Feedbacks(eventid EventID) ([]*Feedback, error)
+	UpdateFeedback(eventid EventID, feedback *Feedback) error
+}
+
+// Feedback is a team response to a single ballot comment.
+type Feedback struct {
+	// Ref is the Ballot.Ref of the comment.
+	Ref    string
+	Aspect string
+	// Voter is the author of the comment.
+	Voter user.UserID
+	Team  TeamID `datastore:",noindex"`
+
+	Reply     string      `datastore:",noindex"`
+	RepliedBy user.UserID `datastore:",noindex"`
+	Replied   time.Time   `datastore:",noindex"`
+
+	// Helpful is set when the team found the comment helpful.
+	Helpful  bool        `datastore:",noindex"`
+	MarkedBy user.UserID `datastore:",noindex"`
+}
+
+// FeedbackInfo is a voter comment together with the team response.
+type FeedbackInfo struct {
+	*Team
+	*Feedback
+	Comment string
+}
+
+// HelpfulReviewer is a voter whose comments teams found helpful.
+type HelpfulReviewer struct {
+	Voter   user.UserID
+	Name    string
+	Helpful int
+}
+
+// feedbackKey identifies a comment.
+type feedbackKey struct{ ref, aspect string }
+
+// feedbacksByComment indexes feedbacks by comment.
+func feedbacksByComment(feedbacks []*Feedback) map[feedbackKey]*Feedback {
+	result := map[feedbackKey]*Feedback{}
+	for _, feedback := range feedbacks {
+		result[feedbackKey{feedback.Ref, feedback.Aspect}] = feedback
+	}
+	return result
+}
+
+// attachFeedback adds team responses to comments.
+func (aspects *AspectsInfo) attachFeedback(feedbacks []*Feedback) {
+	bycomment := feedbacksByComment(feedbacks)
+	attach := func(info *AspectInfo, aspect string) {
+		for i := range info.Comments {
+			comment := &info.Comments[i]
+			if comment.Ref != "" {
+				comment.Feedback = bycomment[feedbackKey{comment.Ref, aspect}]
+			}
+		}
+	}
+	attach(&aspects.Theme, "Theme")
+	attach(&aspects.Enjoyment, "Enjoyment")
+	attach(&aspects.Aesthetics, "Aesthetics")
+	attach(&aspects.Innovation, "Innovation")
+	attach(&aspects.Bonus, "Bonus")
+	attach(&aspects.Overall, "Overall")
+}
+
+// HelpfulReviewers returns voters sorted by the number of comments marked helpful.
+func HelpfulReviewers(feedbacks []*Feedback, users []*user.User) []*HelpfulReviewer {
+	byvoter := map[user.UserID]*HelpfulReviewer{}
+	reviewers := []*HelpfulReviewer{}
+	for _, feedback := range feedbacks {
+		if !feedback.Helpful {
+			continue
+		}
+		reviewer, ok := byvoter[feedback.Voter]
+		if !ok {
+			reviewer = &HelpfulReviewer{Voter: feedback.Voter}
+			if u, ok := findUserByID(users, feedback.Voter); ok {
+				reviewer.Name = u.Name
+			}
+			byvoter[feedback.Voter] = reviewer
+			reviewers = append(reviewers, reviewer)
+		}
+		reviewer.Helpful++
+	}
+
+	sort.Slice(reviewers, func(i, k int) bool {
+		if reviewers[i].Helpful == reviewers[k].Helpful {
+			return reviewers[i].Name < reviewers[k].Name
+		}
+		return reviewers[i].Helpful > reviewers[k].Helpful
+	})
+	return reviewers
+}
+
+// voterFeedback returns responses to comments written by the current user.
+func (server *Server) voterFeedback(context *Context, ballots []*BallotInfo) ([]*FeedbackInfo, error) {
+	feedbacks, err := context.Events.Feedbacks(context.Event.ID)
+	if err != nil {
+		return nil, err
+	}
+	bycomment := feedbacksByComment(feedbacks)
+
+	infos := []*FeedbackInfo{}
+	for _, ballot := range ballots {
+		if ballot.Ref == "" || ballot.Team == nil {
+			continue
+		}
+		for _, aspect := range AspectNames {
+			feedback, ok := bycomment[feedbackKey{ballot.Ref, aspect}]
+			if !ok || (feedback.Reply == "" && !feedback.Helpful) {
+				continue
+			}
+			infos = append(infos, &FeedbackInfo{
+				Team:     ballot.Team,
+				Feedback: feedback,
+				Comment:  ballot.Aspects.Comment(aspect),
+			})
+		}
+	}
+	return infos, nil
+}
+
+// RespondFeedback lets team members reply to a comment and mark it helpful.
+func (server *Server) RespondFeedback(context *Context) {
+	if context.Team == nil {
+		teamid, _ := context.IntParam("teamid")
+		context.FlashError(fmt.Sprintf("Team %v does not exist", teamid))
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	if !context.Team.HasMember(context.CurrentUser) {
+		context.FlashError("Only team members can respond to comments.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	if !context.Event.Revealed {
+		context.FlashError("Comments can be answered after results are revealed.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	if err := context.Request.ParseForm(); err != nil {
+		context.FlashError("Parse form: " + err.Error())
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	ballots, err := context.Events.TeamBallots(context.Event.ID, context.Team.ID)
+	if err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+	reviews, err := server.teamCommentReviews(context, ballots)
+	if err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	ref := context.FormValue("Ref")
+	aspect := context.FormValue("Aspect")
+	var review *CommentReview
+	for _, r := range reviews {
+		if r.Ballot.Ref != "" && r.Ballot.Ref == ref && r.Aspect == aspect && r.Visible() != "" {
+			review = r
+			break
+		}
+	}
+	if review == nil {
+		context.FlashError("Comment does not exist.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	feedbacks, err := context.Events.Feedbacks(context.Event.ID)
+	if err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	feedback, ok := feedbacksByComment(feedbacks)[feedbackKey{ref, aspect}]
+	if !ok {
+		feedback = &Feedback{Ref: ref, Aspect: aspect}
+	}
+	feedback.Voter = review.Ballot.Voter
+	feedback.Team = context.Team.ID
+
+	reply := context.FormValue("Reply")
+	if reply != feedback.Reply {
+		feedback.Reply = reply
+		feedback.RepliedBy = context.CurrentUser.ID
+		feedback.Replied = time.Now().UTC()
+	}
+	helpful := context.FormValue("Helpful") == "true"
+	if helpful != feedback.Helpful {
+		feedback.Helpful = helpful
+		feedback.MarkedBy = context.CurrentUser.ID
+	}
+
+	if err := context.Events.UpdateFeedback(context.Event.ID, feedback); err != nil {
+		context.FlashError(err.Error())
+	} else {
+		context.FlashMessage("Response saved.")
+	}
+	context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+}
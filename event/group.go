@@ -0,0 +1,189 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// DefaultGroupName is the group of voters who don't belong to any other group.
+const DefaultGroupName = "Jammers"
+
+// legacyJudgesName is the group name used for events created with a judge percentage.
+const legacyJudgesName = "Judges"
+
+// VoterGroup is a named group of voters, whose votes are averaged together.
+type VoterGroup struct {
+	Name string
+	// Weight is the percentage of the final score given to the group.
+	Weight  float64
+	Members []user.UserID
+}
+
+// HasMember checks whether userid belongs to the group.
+func (group *VoterGroup) HasMember(userid user.UserID) bool {
+	return containsUser(group.Members, userid)
+}
+
+// VoterGroups returns all weighted voter groups excluding the default group.
+//
+// Events that were configured with JudgePercentage get a single judges group.
+func (event *Event) VoterGroups() []VoterGroup {
+	if len(event.Groups) > 0 {
+		return event.Groups
+	}
+	if event.JudgePercentage > 0 && len(event.Judges) > 0 {
+		return []VoterGroup{{
+			Name:    legacyJudgesName,
+			Weight:  event.JudgePercentage,
+			Members: event.Judges,
+		}}
+	}
+	return nil
+}
+
+// ScoreGroups returns all voter groups, where the first is the default group.
+func (event *Event) ScoreGroups() []VoterGroup {
+	groups := []VoterGroup{{
+		Name:   DefaultGroupName,
+		Weight: event.DefaultGroupWeight(),
+	}}
+	return append(groups, event.VoterGroups()...)
+}
+
+// DefaultGroupWeight returns the weight left over for the default group.
+func (event *Event) DefaultGroupWeight() float64 {
+	weight := 100.0
+	for _, group := range event.VoterGroups() {
+		weight -= group.Weight
+	}
+	return clamped(weight, 0, 100)
+}
+
+// GroupsExist returns whether any voters are weighted separately.
+func (event *Event) GroupsExist() bool {
+	return len(event.VoterGroups()) > 0
+}
+
+// VoterGroup returns the group name userid belongs to.
+func (event *Event) VoterGroup(userid user.UserID) string {
+	for _, group := range event.VoterGroups() {
+		if group.HasMember(userid) {
+			return group.Name
+		}
+	}
+	return DefaultGroupName
+}
+
+// UserVoterGroup returns the group name u belongs to.
+func (event *Event) UserVoterGroup(u *user.User) string {
+	if u == nil {
+		return DefaultGroupName
+	}
+	return event.VoterGroup(u.ID)
+}
+
+// GroupsForEdit returns voter groups with additional empty groups for adding.
+func (event *Event) GroupsForEdit() []VoterGroup {
+	groups := append([]VoterGroup{}, event.VoterGroups()...)
+	return append(groups, VoterGroup{}, VoterGroup{})
+}
+
+// migrateGroups converts legacy judge configuration to Groups.
+func (event *Event) migrateGroups() {
+	if len(event.Groups) == 0 {
+		event.Groups = event.VoterGroups()
+	}
+	event.Judges = nil
+	event.JudgePercentage = 0
+}
+
+// SetVoterGroup moves userid to the named group.
+//
+// Using DefaultGroupName removes userid from all groups.
+func (event *Event) SetVoterGroup(userid user.UserID, name string) error {
+	event.migrateGroups()
+
+	found := name == DefaultGroupName
+	for _, group := range event.Groups {
+		found = found || group.Name == name
+	}
+	if !found {
+		return fmt.Errorf("voter group %q does not exist", name)
+	}
+
+	for i := range event.Groups {
+		group := &event.Groups[i]
+		group.Members = removeUser(group.Members, userid)
+		if group.Name == name {
+			group.Members = append(group.Members, userid)
+		}
+	}
+	return nil
+}
+
+// SetGroups replaces group names and weights, keeping their members.
+//
+// Groups are matched to existing ones by their previous names.
+func (event *Event) SetGroups(previous []string, groups []VoterGroup) error {
+	if err := verifyGroups(groups); err != nil {
+		return err
+	}
+
+	existing := event.VoterGroups()
+	for i := range groups {
+		if i >= len(previous) {
+			continue
+		}
+		for _, old := range existing {
+			if old.Name == previous[i] {
+				groups[i].Members = old.Members
+				break
+			}
+		}
+	}
+
+	event.Groups = groups
+	event.Judges = nil
+	event.JudgePercentage = 0
+	return nil
+}
+
+// verifyGroups checks that groups have unique names and valid weights.
+func verifyGroups(groups []VoterGroup) error {
+	total := 0.0
+	for i, group := range groups {
+		if group.Name == "" {
+			return errors.New("voter group name cannot be empty")
+		}
+		if strings.EqualFold(group.Name, DefaultGroupName) {
+			return fmt.Errorf("voter group cannot be named %q", DefaultGroupName)
+		}
+		for _, other := range groups[:i] {
+			if strings.EqualFold(other.Name, group.Name) {
+				return fmt.Errorf("voter group %q is defined twice", group.Name)
+			}
+		}
+		if group.Weight < 0 || group.Weight > 100 {
+			return fmt.Errorf("voter group %q weight must be between 0 and 100", group.Name)
+		}
+		total += group.Weight
+	}
+	if total > 100 {
+		return fmt.Errorf("voter group weights add up to %v%%, which is more than 100%%", total)
+	}
+	return nil
+}
+
+// removeUser returns userids without userid.
+func removeUser(userids []user.UserID, userid user.UserID) []user.UserID {
+	result := []user.UserID{}
+	for _, id := range userids {
+		if id != userid {
+			result = append(result, id)
+		}
+	}
+	return result
+}
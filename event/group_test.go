@@ -0,0 +1,45 @@
+package event
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+func TestRemoveUser(t *testing.T) {
+	userids := []user.UserID{1, 2, 3, 2}
+	result := removeUser(userids, 2)
+	if !slices.Equal(result, []user.UserID{1, 3}) {
+		t.Errorf("got %v", result)
+	}
+	if !slices.Equal(userids, []user.UserID{1, 2, 3, 2}) {
+		t.Errorf("input was modified: %v", userids)
+	}
+}
+
+func TestSetVoterGroup(t *testing.T) {
+	event := &Event{Groups: []VoterGroup{
+		{Name: "Judges", Weight: 30, Members: []user.UserID{1, 2}},
+		{Name: "Guests", Weight: 10},
+	}}
+
+	if err := event.SetVoterGroup(1, "Guests"); err != nil {
+		t.Fatal(err)
+	}
+	if event.VoterGroup(1) != "Guests" || event.VoterGroup(2) != "Judges" {
+		t.Errorf("got %q and %q", event.VoterGroup(1), event.VoterGroup(2))
+	}
+	if err := event.SetVoterGroup(2, DefaultGroupName); err != nil {
+		t.Fatal(err)
+	}
+	if event.VoterGroup(2) != DefaultGroupName {
+		t.Errorf("got %q", event.VoterGroup(2))
+	}
+	if err := event.SetVoterGroup(3, "Missing"); err == nil {
+		t.Error("expected error for missing group")
+	}
+	if weight := event.DefaultGroupWeight(); weight != 60 {
+		t.Errorf("default weight: got %v", weight)
+	}
+}
@@ -0,0 +1,264 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/adinfinit/jamvote/internal/imaging"
+)
+
+const (
+	// MaxImageUpload is the maximum size of a single uploaded image.
+	MaxImageUpload = 8 << 20
+	// MaxScreenshots is the maximum number of screenshots per game.
+	MaxScreenshots = 6
+
+	// ImageWidth and ImageHeight bound the stored image size.
+	ImageWidth  = 1920
+	ImageHeight = 1080
+	// ThumbWidth and ThumbHeight bound the thumbnail size.
+	ThumbWidth  = 480
+	ThumbHeight = 270
+)
+
+// imagePrefix is the blob key prefix of uploaded images.
+const imagePrefix = "games/"
+
+// PublicBlob reports whether key is an uploaded image, which can be served as is.
+//
+// Other blobs, such as hosted builds, are untrusted and only served by PlayBuild.
+func PublicBlob(key string) bool {
+	return strings.HasPrefix(key, imagePrefix) && path.Ext(key) == ".jpg"
+}
+
+// Image is an uploaded image together with its thumbnail.
+type Image struct {
+	// Key is the blob key of the resized image.
+	Key string
+	// Thumb is the blob key of the thumbnail.
+	Thumb string
+
+	Width  int
+	Height int
+}
+
+// Empty returns whether no image has been uploaded.
+func (image Image) Empty() bool { return image.Key == "" }
+
+// URL returns the location of the image.
+func (image Image) URL() string { return "/blob/" + image.Key }
+
+// ThumbURL returns the location of the thumbnail.
+func (image Image) ThumbURL() string { return "/blob/" + image.Thumb }
+
+// Thumbnail returns the image to show in listings,
+// the cover or when missing the first screenshot.
+func (game *Game) Thumbnail() Image {
+	if !game.Cover.Empty() || len(game.Screenshots) == 0 {
+		return game.Cover
+	}
+	return game.Screenshots[0]
+}
+
+// storeImage validates, resizes and stores an uploaded image.
+func (server *Server) storeImage(context *Context, data []byte) (Image, error) {
+	m, err := imaging.Decode(data)
+	if err != nil {
+		return Image{}, err
+	}
+
+	full := imaging.Fit(m, ImageWidth, ImageHeight)
+	fullData, err := imaging.EncodeJPEG(full)
+	if err != nil {
+		return Image{}, err
+	}
+	thumbData, err := imaging.EncodeJPEG(imaging.Fit(full, ThumbWidth, ThumbHeight))
+	if err != nil {
+		return Image{}, err
+	}
+
+	name := fmt.Sprintf("%v%v/%v/%v", imagePrefix, context.Event.ID, context.Team.ID, newToken())
+	image := Image{
+		Key:    name + ".jpg",
+		Thumb:  name + "-thumb.jpg",
+		Width:  full.Bounds().Dx(),
+		Height: full.Bounds().Dy(),
+	}
+	if err := server.Blobs.Put(context, image.Key, fullData); err != nil {
+		return Image{}, fmt.Errorf("unable to store image: %w", err)
+	}
+	if err := server.Blobs.Put(context, image.Thumb, thumbData); err != nil {
+		server.deleteImages(context, Image{Key: image.Key})
+		return Image{}, fmt.Errorf("unable to store thumbnail: %w", err)
+	}
+	return image, nil
+}
+
+// deleteImages removes stored images, failures are only logged.
+func (server *Server) deleteImages(context *Context, images ...Image) {
+	for _, image := range images {
+		if image.Empty() {
+			continue
+		}
+		for _, key := range []string{image.Key, image.Thumb} {
+			if key == "" {
+				continue
+			}
+			if err := server.Blobs.Delete(context, key); err != nil {
+				context.Site.Log.Error("failed to delete image", "key", key, "error", err)
+			}
+		}
+	}
+}
+
+// readUpload reads an uploaded file, rejecting files over MaxImageUpload.
+func readUpload(header *multipart.FileHeader) ([]byte, error) {
+	if header.Size > MaxImageUpload {
+		return nil, fmt.Errorf("%v is larger than %vMB", header.Filename, MaxImageUpload>>20)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(io.LimitReader(file, MaxImageUpload))
+}
+
+// TeamImages handles uploading and removing the cover and screenshots of a game.
+func (server *Server) TeamImages(context *Context) {
+	if !server.canEditTeam(context) {
+		return
+	}
+	if server.Blobs == nil {
+		context.FlashError("Image uploads have not been configured.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	context.Data["MaxScreenshots"] = MaxScreenshots
+	context.Data["MaxImageUploadMB"] = MaxImageUpload >> 20
+	if context.Request.Method != http.MethodPost {
+		context.Render("event-team-images")
+		return
+	}
+
+	back := context.Event.Path("team", context.Team.ID, "images")
+	context.Request.Body = http.MaxBytesReader(context.Response, context.Request.Body, (MaxScreenshots+1)*MaxImageUpload)
+	if err := context.Request.ParseMultipartForm(MaxImageUpload); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			context.FlashError("Upload is too large.")
+		} else {
+			context.FlashError(fmt.Sprintf("Invalid upload: %v", err))
+		}
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+
+	team := context.Team
+	game := &team.Game
+	added, removed := []Image{}, []Image{}
+
+	var message string
+	switch context.FormValue("action") {
+	case "cover":
+		files := context.Request.MultipartForm.File["Cover"]
+		if len(files) != 1 {
+			context.FlashError("Select an image to upload.")
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		data, err := readUpload(files[0])
+		if err == nil {
+			var image Image
+			image, err = server.storeImage(context, data)
+			if err == nil {
+				added = append(added, image)
+				removed = append(removed, game.Cover)
+				game.Cover = image
+			}
+		}
+		if err != nil {
+			context.FlashError(err.Error())
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		message = "Cover updated."
+	case "screenshots":
+		files := context.Request.MultipartForm.File["Screenshots"]
+		if len(files) == 0 {
+			context.FlashError("Select images to upload.")
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		if len(game.Screenshots)+len(files) > MaxScreenshots {
+			context.FlashError(fmt.Sprintf("Game can have at most %v screenshots.", MaxScreenshots))
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		}
+		for _, file := range files {
+			data, err := readUpload(file)
+			if err == nil {
+				var image Image
+				image, err = server.storeImage(context, data)
+				if err == nil {
+					added = append(added, image)
+					game.Screenshots = append(game.Screenshots, image)
+				}
+			}
+			if err != nil {
+				context.FlashError(fmt.Sprintf("%v: %v", file.Filename, err))
+				break
+			}
+		}
+		switch {
+		case len(added) == 0:
+			context.Redirect(back, http.StatusSeeOther)
+			return
+		case len(added) < len(files):
+			message = fmt.Sprintf("Uploaded %v of %v screenshots.", len(added), len(files))
+		default:
+			message = "Screenshots uploaded."
+		}
+	case "remove":
+		key := context.FormValue("key")
+		switch {
+		case key != "" && game.Cover.Key == key:
+			removed = append(removed, game.Cover)
+			game.Cover = Image{}
+		default:
+			index := slices.IndexFunc(game.Screenshots, func(image Image) bool {
+				return image.Key == key
+			})
+			if index < 0 {
+				context.FlashError("Image does not exist.")
+				context.Redirect(back, http.StatusSeeOther)
+				return
+			}
+			removed = append(removed, game.Screenshots[index])
+			game.Screenshots = slices.Delete(game.Screenshots, index, index+1)
+		}
+		message = "Image removed."
+	default:
+		context.FlashError("Unknown image action.")
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+
+	if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+		server.deleteImages(context, added...)
+		context.FlashError(fmt.Sprintf("Unable to update team: %v", err))
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+	server.deleteImages(context, removed...)
+
+	context.FlashMessage(message)
+	context.Redirect(back, http.StatusSeeOther)
+}
@@ -0,0 +1,449 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// Invite is an invitation to join a team.
+type Invite struct {
+	// Token identifies the invitation in links.
+	Token string
+	// User is the invited user, zero for shareable links.
+	User      user.UserID
+	InvitedBy user.UserID
+	Created   time.Time
+}
+
+// IsLink returns whether the invite is a shareable link usable by anyone.
+func (invite *Invite) IsLink() bool { return invite.User == 0 }
+
+// JoinRequest is a request by a user to join a team.
+type JoinRequest struct {
+	User user.UserID
+	// Member is the unregistered member the user claims to be,
+	// empty when joining as a new member.
+	Member  string
+	Created time.Time
+}
+
+// ErrTeamFull is returned when a team cannot take more members.
+var ErrTeamFull = errors.New("team is full")
+
+// InviteByToken finds an invitation by its token.
+func (team *Team) InviteByToken(token string) (*Invite, bool) {
+	for i := range team.Invites {
+		if team.Invites[i].Token == token {
+			return &team.Invites[i], true
+		}
+	}
+	return nil, false
+}
+
+// InviteFor finds the invitation for userid.
+func (team *Team) InviteFor(userid user.UserID) (*Invite, bool) {
+	for i := range team.Invites {
+		if team.Invites[i].User == userid && userid != 0 {
+			return &team.Invites[i], true
+		}
+	}
+	return nil, false
+}
+
+// HasJoinRequest checks whether userid has requested to join the team.
+func (team *Team) HasJoinRequest(userid user.UserID) bool {
+	return slices.ContainsFunc(team.JoinRequests, func(request JoinRequest) bool {
+		return request.User == userid
+	})
+}
+
+// AddInvite invites u to the team, or creates a shareable link when u is nil.
+func (team *Team) AddInvite(u *user.User, invitedBy user.UserID) (*Invite, error) {
+	invite := Invite{
+		Token:     newToken(),
+		InvitedBy: invitedBy,
+		Created:   time.Now().UTC(),
+	}
+	if u != nil {
+		if team.HasMember(u) {
+			return nil, fmt.Errorf("%v is already a member", u.Name)
+		}
+		if _, ok := team.InviteFor(u.ID); ok {
+			return nil, fmt.Errorf("%v has already been invited", u.Name)
+		}
+		invite.User = u.ID
+	}
+	team.Invites = append(team.Invites, invite)
+	return &team.Invites[len(team.Invites)-1], nil
+}
+
+// AddJoinRequest adds a request by u to join the team as the unregistered member.
+//
+// Use an empty member to join as a new member.
+func (team *Team) AddJoinRequest(u *user.User, member string) error {
+	if team.HasMember(u) {
+		return errors.New("you are already a member")
+	}
+	if team.HasJoinRequest(u.ID) {
+		return errors.New("you have already requested to join")
+	}
+	if member != "" {
+		if _, ok := team.unregisteredMember(member); !ok {
+			return fmt.Errorf("%q is not an unregistered member of %v", member, team.Name)
+		}
+	}
+	team.JoinRequests = append(team.JoinRequests, JoinRequest{
+		User:    u.ID,
+		Member:  member,
+		Created: time.Now().UTC(),
+	})
+	return nil
+}
+
+// joinRequest finds the join request of userid.
+func (team *Team) joinRequest(userid user.UserID) (JoinRequest, bool) {
+	for _, request := range team.JoinRequests {
+		if request.User == userid {
+			return request, true
+		}
+	}
+	return JoinRequest{}, false
+}
+
+// ApproveJoinRequest adds u to the team, which can have at most limit members.
+//
+// When u asked to be an unregistered member, the member is linked to u.
+func (team *Team) ApproveJoinRequest(u *user.User, limit int) error {
+	request, ok := team.joinRequest(u.ID)
+	if !ok {
+		return fmt.Errorf("%v has not requested to join", u.Name)
+	}
+	if request.Member == "" {
+		return team.AddMember(u, limit)
+	}
+	if err := team.LinkMember(request.Member, u); err != nil {
+		return err
+	}
+	team.removePending(u.ID)
+	return nil
+}
+
+// AddMember adds u to the team, which can have at most limit members.
+//
+// An unregistered member with the same name is linked to u instead of adding
+// a new member. Pending invitations and join requests of u are removed.
+func (team *Team) AddMember(u *user.User, limit int) error {
+	if team.HasMember(u) {
+		return fmt.Errorf("%v is already a member", u.Name)
+	}
+
+	linked := false
+	for i, member := range team.Members {
+		if member.ID == 0 && strings.EqualFold(member.Name, u.Name) {
+			team.Members[i] = Member{ID: u.ID, Name: u.Name}
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		if len(team.Members) >= limit {
+			return ErrTeamFull
+		}
+		team.Members = append(team.Members, Member{ID: u.ID, Name: u.Name})
+	}
+
+	team.removePending(u.ID)
+	return nil
+}
+
+// removePending removes invitations and join requests of userid.
+func (team *Team) removePending(userid user.UserID) {
+	team.Invites = slices.DeleteFunc(team.Invites, func(invite Invite) bool {
+		return invite.User == userid
+	})
+	team.RemoveJoinRequest(userid)
+}
+
+// RemoveInvite removes the invitation with token.
+func (team *Team) RemoveInvite(token string) bool {
+	count := len(team.Invites)
+	team.Invites = slices.DeleteFunc(team.Invites, func(invite Invite) bool {
+		return invite.Token == token
+	})
+	return len(team.Invites) != count
+}
+
+// RemoveJoinRequest removes the join request of userid.
+func (team *Team) RemoveJoinRequest(userid user.UserID) bool {
+	count := len(team.JoinRequests)
+	team.JoinRequests = slices.DeleteFunc(team.JoinRequests, func(request JoinRequest) bool {
+		return request.User == userid
+	})
+	return len(team.JoinRequests) != count
+}
+
+// PendingInvites returns teams where u has an unanswered invitation.
+func PendingInvites(teams []*Team, u *user.User) []*Team {
+	pending := []*Team{}
+	if u == nil {
+		return pending
+	}
+	for _, team := range teams {
+		if _, ok := team.InviteFor(u.ID); ok {
+			pending = append(pending, team)
+		}
+	}
+	return pending
+}
+
+// teamMembership adds membership information for the team page.
+func (server *Server) teamMembership(context *Context) {
+	team := context.Team
+	current := context.CurrentUser
+	if current == nil {
+		return
+	}
+
+	if invite, ok := team.InviteFor(current.ID); ok {
+		context.Data["PendingInvite"] = invite
+	}
+	context.Data["CanRequestJoin"] = !team.HasMember(current) &&
+		context.Event.CanRegister(current)
+	context.Data["HasJoinRequest"] = team.HasJoinRequest(current.ID)
+
+	if !team.HasEditor(current) {
+		return
+	}
+
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get list of users: %v", err))
+	}
+
+	names := map[user.UserID]string{}
+	candidates := []*user.User{}
+	for _, u := range users {
+		names[u.ID] = u.Name
+		if _, invited := team.InviteFor(u.ID); !invited && !team.HasMember(u) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	links := map[string]string{}
+	for i := range team.Invites {
+		if team.Invites[i].IsLink() {
+			links[team.Invites[i].Token] = server.inviteURL(context, &team.Invites[i])
+		}
+	}
+
+	context.Data["UserNames"] = names
+	context.Data["InviteLinks"] = links
+	context.Data["InviteCandidates"] = candidates
+	context.Data["ManageMembership"] = true
+}
+
+// inviteURL returns the absolute link for accepting invite.
+func (server *Server) inviteURL(context *Context, invite *Invite) string {
+	return server.absoluteURL(context.Event.Path("team", context.Team.ID, "invite", invite.Token))
+}
+
+// TeamMembership handles invitations and join requests of a team.
+func (server *Server) TeamMembership(context *Context) {
+	if context.Team == nil {
+		teamid, _ := context.IntParam("teamid")
+		context.FlashError(fmt.Sprintf("Team %v does not exist.", teamid))
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+	if context.CurrentUser == nil {
+		context.FlashError("You must be logged in to join teams.")
+		context.Redirect("/user/login", http.StatusSeeOther)
+		return
+	}
+	if context.Request.Method != http.MethodPost {
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	team := context.Team
+	current := context.CurrentUser
+	isEditor := team.HasEditor(current)
+	canRegister := context.Event.CanRegister(current)
+	back := context.Event.Path("team", team.ID)
+
+	fail := func(message string) {
+		context.FlashError(message)
+		context.Redirect(back, http.StatusSeeOther)
+	}
+
+	formUser := func() (*user.User, bool) {
+		id, err := strconv.ParseInt(context.FormValue("user"), 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		u, err := context.Users.ByID(user.UserID(id))
+		return u, err == nil && u != nil
+	}
+
+	var message string
+	switch action := context.FormValue("action"); action {
+	case "invite", "invite-link":
+		if !isEditor {
+			fail("Only team members can invite.")
+			return
+		}
+		if !canRegister {
+			fail("Registration is closed.")
+			return
+		}
+		var invitee *user.User
+		if action == "invite" {
+			u, ok := formUser()
+			if !ok {
+				fail("User does not exist.")
+				return
+			}
+			invitee = u
+		}
+		invite, err := team.AddInvite(invitee, current.ID)
+		if err != nil {
+			fail(err.Error())
+			return
+		}
+		if invitee != nil {
+			message = fmt.Sprintf("Invited %v.", invitee.Name)
+		} else {
+			message = fmt.Sprintf("Created invite link %v", server.inviteURL(context, invite))
+		}
+	case "revoke":
+		if !isEditor {
+			fail("Only team members can revoke invitations.")
+			return
+		}
+		if !team.RemoveInvite(context.FormValue("token")) {
+			fail("Invitation does not exist.")
+			return
+		}
+		message = "Invitation revoked."
+	case "accept":
+		invite, ok := team.InviteByToken(context.FormValue("token"))
+		if !ok || (!invite.IsLink() && invite.User != current.ID) {
+			fail("Invitation does not exist or has been revoked.")
+			return
+		}
+		if !canRegister {
+			fail("Registration is closed.")
+			return
+		}
+		if err := server.canJoinTeam(context, team, current); err != nil {
+			fail(err.Error())
+			return
+		}
+		if err := team.AddMember(current, context.Event.TeamRules.MemberLimit()); err != nil {
+			fail(err.Error())
+			return
+		}
+		message = fmt.Sprintf("You joined %v.", team.Name)
+	case "decline":
+		invite, ok := team.InviteFor(current.ID)
+		if !ok {
+			fail("Invitation does not exist.")
+			return
+		}
+		team.RemoveInvite(invite.Token)
+		message = "Invitation declined."
+	case "request":
+		if !canRegister {
+			fail("Registration is closed.")
+			return
+		}
+		if err := team.AddJoinRequest(current, ""); err != nil {
+			fail(err.Error())
+			return
+		}
+		message = "Requested to join the team."
+	case "cancel":
+		if !team.RemoveJoinRequest(current.ID) {
+			fail("Join request does not exist.")
+			return
+		}
+		message = "Join request cancelled."
+	case "approve", "reject":
+		if !isEditor {
+			fail("Only team members can answer join requests.")
+			return
+		}
+		u, ok := formUser()
+		if !ok || !team.HasJoinRequest(u.ID) {
+			fail("Join request does not exist.")
+			return
+		}
+		if action == "reject" {
+			team.RemoveJoinRequest(u.ID)
+			message = fmt.Sprintf("Rejected %v.", u.Name)
+			break
+		}
+		if !canRegister {
+			fail("Registration is closed.")
+			return
+		}
+		if err := server.canJoinTeam(context, team, u); err != nil {
+			fail(err.Error())
+			return
+		}
+		if err := team.ApproveJoinRequest(u, context.Event.TeamRules.MemberLimit()); err != nil {
+			fail(err.Error())
+			return
+		}
+		message = fmt.Sprintf("%v joined the team.", u.Name)
+	default:
+		fail("Unknown membership action.")
+		return
+	}
+
+	if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+		fail(fmt.Sprintf("Unable to update team: %v", err))
+		return
+	}
+
+	context.FlashMessage(message)
+	context.Redirect(back, http.StatusSeeOther)
+}
+
+// TeamInvite displays an invitation for accepting or declining.
+func (server *Server) TeamInvite(context *Context) {
+	if context.Team == nil {
+		teamid, _ := context.IntParam("teamid")
+		context.FlashError(fmt.Sprintf("Team %v does not exist.", teamid))
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+	if context.CurrentUser == nil {
+		context.FlashError("You must be logged in to accept an invitation.")
+		context.Redirect("/user/login", http.StatusSeeOther)
+		return
+	}
+
+	token, _ := context.StringParam("token")
+	invite, ok := context.Team.InviteByToken(token)
+	if !ok || (!invite.IsLink() && invite.User != context.CurrentUser.ID) {
+		context.FlashError("Invitation does not exist or has been revoked.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+	if context.Team.HasMember(context.CurrentUser) {
+		context.FlashMessage(fmt.Sprintf("You are already a member of %v.", context.Team.Name))
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	context.Data["Invite"] = invite
+	context.Render("event-team-invite")
+}
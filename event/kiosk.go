@@ -0,0 +1,211 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// KioskOrder is the order in which the kiosk cycles through teams.
+type KioskOrder string
+
+const (
+	// KioskOrderName shows teams ordered by name.
+	KioskOrderName KioskOrder = "name"
+	// KioskOrderRandom shows teams in a random order, which changes when settings are saved.
+	KioskOrderRandom KioskOrder = "random"
+)
+
+// KioskOrders contains all kiosk orders.
+var KioskOrders = []KioskOrder{KioskOrderName, KioskOrderRandom}
+
+const (
+	// DefaultKioskInterval is the default number of seconds each team is shown.
+	DefaultKioskInterval = 30
+	// MinKioskInterval is the minimum number of seconds each team is shown.
+	MinKioskInterval = 5
+	// kioskPollInterval is how often the kiosk page checks for organizer changes.
+	kioskPollInterval = 5 * time.Second
+)
+
+// Kiosk contains the showcase kiosk settings and organizer controls.
+type Kiosk struct {
+	// Interval is the number of seconds each team is shown.
+	Interval int
+	Order    KioskOrder
+	// Paused stops the kiosk from advancing to the next team.
+	Paused bool
+	// Current is the team the organizer jumped to.
+	Current TeamID
+	// Jumped is the time the organizer jumped to Current.
+	Jumped time.Time
+	// Shuffled is the time settings were saved, used for the random order.
+	Shuffled time.Time
+}
+
+// IntervalSeconds returns the interval, using the default when unset.
+func (kiosk *Kiosk) IntervalSeconds() int {
+	if kiosk.Interval <= 0 {
+		return DefaultKioskInterval
+	}
+	return max(kiosk.Interval, MinKioskInterval)
+}
+
+// JumpedMillis returns Jumped in Unix milliseconds, or 0 when never jumped.
+func (kiosk *Kiosk) JumpedMillis() int64 {
+	if kiosk.Jumped.IsZero() {
+		return 0
+	}
+	return kiosk.Jumped.UnixMilli()
+}
+
+// OrderTeams returns competing teams in the kiosk order.
+func (kiosk *Kiosk) OrderTeams(eventid EventID, submission SubmissionRules, teams []*Team) []*Team {
+	ordered := []*Team{}
+	for _, team := range teams {
+		if team.IsCompeting(submission) {
+			ordered = append(ordered, team)
+		}
+	}
+	sort.Slice(ordered, func(i, k int) bool {
+		return ordered[i].Less(ordered[k])
+	})
+
+	if kiosk.Order == KioskOrderRandom {
+		hash := fnv.New64a()
+		fmt.Fprint(hash, eventid, kiosk.Shuffled.UnixNano())
+		rng := rand.New(rand.NewSource(int64(hash.Sum64())))
+		rng.Shuffle(len(ordered), func(i, k int) {
+			ordered[i], ordered[k] = ordered[k], ordered[i]
+		})
+	}
+	return ordered
+}
+
+// kioskState is the kiosk state polled by the kiosk page.
+type kioskState struct {
+	Interval int      `json:"interval"`
+	Paused   bool     `json:"paused"`
+	Current  TeamID   `json:"current"`
+	Jumped   int64    `json:"jumped"`
+	Teams    []TeamID `json:"teams"`
+}
+
+// kioskTeams returns teams shown in the kiosk.
+func (server *Server) kioskTeams(context *Context) []*Team {
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	return context.Event.Kiosk.OrderTeams(context.Event.ID, context.Event.Submission, teams)
+}
+
+// Kiosk displays a fullscreen page cycling through competing teams.
+func (server *Server) Kiosk(context *Context) {
+	printed := []*PrintedTeam{}
+	for _, team := range server.kioskTeams(context) {
+		printed = append(printed, server.printedTeam(context, team))
+	}
+
+	context.Data["Teams"] = printed
+	context.Data["Kiosk"] = &context.Event.Kiosk
+	context.Data["PollInterval"] = int(kioskPollInterval / time.Millisecond)
+	context.Render("event-kiosk")
+}
+
+// KioskState returns the current kiosk state as JSON.
+func (server *Server) KioskState(context *Context) {
+	kiosk := &context.Event.Kiosk
+	state := kioskState{
+		Interval: kiosk.IntervalSeconds(),
+		Paused:   kiosk.Paused,
+		Current:  kiosk.Current,
+		Jumped:   kiosk.JumpedMillis(),
+		Teams:    []TeamID{},
+	}
+	for _, team := range server.kioskTeams(context) {
+		state.Teams = append(state.Teams, team.ID)
+	}
+
+	context.Response.Header().Set("Content-Type", "application/json")
+	context.Response.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(context.Response).Encode(state); err != nil {
+		context.Site.Log.Error("failed to encode kiosk state", "error", err)
+	}
+}
+
+// KioskControl handles organizer controls for the kiosk.
+func (server *Server) KioskControl(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to control the kiosk.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	teams := server.kioskTeams(context)
+	context.Data["Teams"] = teams
+	context.Data["Kiosk"] = &context.Event.Kiosk
+	context.Data["KioskOrders"] = KioskOrders
+	context.Data["MinKioskInterval"] = MinKioskInterval
+
+	if context.Request.Method != http.MethodPost {
+		context.Render("event-kiosk-control")
+		return
+	}
+
+	fail := func(message string) {
+		context.FlashErrorNow(message)
+		context.Response.WriteHeader(http.StatusBadRequest)
+		context.Render("event-kiosk-control")
+	}
+
+	kiosk := &context.Event.Kiosk
+	switch context.FormValue("action") {
+	case "settings":
+		interval, err := strconv.Atoi(context.FormValue("interval"))
+		if err != nil || interval < MinKioskInterval {
+			fail(fmt.Sprintf("Interval must be at least %v seconds.", MinKioskInterval))
+			return
+		}
+		order := KioskOrder(context.FormValue("order"))
+		if !slices.Contains(KioskOrders, order) {
+			fail(fmt.Sprintf("Unknown order %q.", order))
+			return
+		}
+		kiosk.Interval = interval
+		kiosk.Order = order
+		kiosk.Shuffled = time.Now().UTC()
+	case "pause":
+		kiosk.Paused = true
+	case "resume":
+		kiosk.Paused = false
+	case "jump":
+		teamid, err := strconv.ParseInt(context.FormValue("team"), 10, 64)
+		found := false
+		for _, team := range teams {
+			found = found || (err == nil && team.ID == TeamID(teamid))
+		}
+		if !found {
+			fail("The team is not shown in the kiosk.")
+			return
+		}
+		kiosk.Current = TeamID(teamid)
+		kiosk.Jumped = time.Now().UTC()
+	default:
+		fail("Unknown kiosk action.")
+		return
+	}
+
+	if err := context.Events.Update(context.Event); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	context.Redirect(context.Event.Path("kiosk", "control"), http.StatusSeeOther)
+}
@@ -0,0 +1,210 @@
+package event
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// LeaderboardAspects are the aspects that have a separate leaderboard.
+var LeaderboardAspects = []string{"Theme", "Enjoyment", "Aesthetics", "Innovation", "Bonus"}
+
+// Leaderboard ranks teams by a single aspect.
+type Leaderboard struct {
+	Aspect  string
+	Groups  []VoterGroup
+	Entries []*LeaderboardEntry
+}
+
+// LeaderboardEntry is a single team in a leaderboard.
+type LeaderboardEntry struct {
+	*TeamResult
+	// Place is the placement by the aspect, zero for teams that are not ranked.
+	Place       int
+	SharedPlace bool
+	Score       float64
+	GroupScores []GroupScore
+}
+
+// GroupScore is the aspect average of a single voter group.
+type GroupScore struct {
+	Name  string
+	Count int
+	Score float64
+}
+
+// NewLeaderboard ranks results by the named aspect.
+//
+// Teams with equal scores share the placement.
+func NewLeaderboard(aspect string, results []*TeamResult, event *Event) *Leaderboard {
+	board := &Leaderboard{
+		Aspect: aspect,
+		Groups: event.ScoreGroups(),
+	}
+
+	for _, result := range results {
+		entry := &LeaderboardEntry{
+			TeamResult: result,
+			Score:      result.Average.Score(aspect),
+		}
+		for _, group := range board.Groups {
+			average := result.GroupAverage(group.Name)
+			entry.GroupScores = append(entry.GroupScores, GroupScore{
+				Name:  group.Name,
+				Count: average.Count,
+				Score: average.Average.Score(aspect),
+			})
+		}
+		board.Entries = append(board.Entries, entry)
+	}
+
+	places, shared := rankPlaces(board.Entries,
+		func(entry *LeaderboardEntry) bool { return entry.IsRanked(event.Submission) },
+		func(a, b *LeaderboardEntry) int { return compareScores(a.Score, b.Score) },
+		func(a, b *LeaderboardEntry) bool { return a.Team.Less(b.Team) },
+	)
+	for i, entry := range board.Entries {
+		entry.Place = places[i]
+		entry.SharedPlace = shared[i]
+	}
+
+	return board
+}
+
+// leaderboard loads the leaderboard for the aspect in the request.
+func (server *Server) leaderboard(context *Context) (*Leaderboard, bool) {
+	aspect, _ := context.StringParam("aspect")
+	if !slices.Contains(LeaderboardAspects, aspect) {
+		context.FlashError(fmt.Sprintf("Leaderboard %q does not exist.", aspect))
+		context.Redirect(context.Event.Path("results"), http.StatusSeeOther)
+		return nil, false
+	}
+
+	results, err := context.Events.Results(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	return NewLeaderboard(aspect, results, context.Event), true
+}
+
+// AspectResults displays results ranked by a single aspect.
+func (server *Server) AspectResults(context *Context) {
+	if !server.canViewResults(context) {
+		return
+	}
+
+	board, ok := server.leaderboard(context)
+	if !ok {
+		return
+	}
+
+	context.Data["Leaderboard"] = board
+	context.Data["LeaderboardAspects"] = LeaderboardAspects
+	context.Render("event-results-aspect")
+}
+
+// AspectResultsCSV downloads results ranked by a single aspect.
+func (server *Server) AspectResultsCSV(context *Context) {
+	if !server.canViewResults(context) {
+		return
+	}
+
+	board, ok := server.leaderboard(context)
+	if !ok {
+		return
+	}
+
+	context.Response.Header().Set("Content-Type", "text/csv")
+	context.Response.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%v-%v.csv", context.Event.ID, board.Aspect)))
+
+	writer := csv.NewWriter(context.Response)
+	defer writer.Flush()
+
+	header := []string{"Place", "TeamID", "TeamName", "GameName", "Noncompeting", board.Aspect}
+	for _, group := range board.Groups {
+		header = append(header, group.Name+"Votes", group.Name+board.Aspect)
+	}
+	_ = writer.Write(header)
+
+	for _, entry := range board.Entries {
+		place := ""
+		if entry.Place > 0 {
+			place = strconv.Itoa(entry.Place)
+		}
+		row := []string{
+			place,
+			entry.Team.ID.String(),
+			entry.Team.Name,
+			entry.Team.Game.Name,
+			strconv.FormatBool(entry.Team.Game.Noncompeting),
+			fmt.Sprintf("%.3f", entry.Score),
+		}
+		for _, score := range entry.GroupScores {
+			row = append(row, strconv.Itoa(score.Count), fmt.Sprintf("%.3f", score.Score))
+		}
+		_ = writer.Write(row)
+	}
+}
+
+// AspectResultsJSON downloads results ranked by a single aspect.
+func (server *Server) AspectResultsJSON(context *Context) {
+	if !server.canViewResults(context) {
+		return
+	}
+
+	board, ok := server.leaderboard(context)
+	if !ok {
+		return
+	}
+
+	type groupScore struct {
+		Group string  `json:"group"`
+		Votes int     `json:"votes"`
+		Score float64 `json:"score"`
+	}
+	type entry struct {
+		Place        int          `json:"place,omitempty"`
+		SharedPlace  bool         `json:"sharedPlace,omitempty"`
+		TeamID       TeamID       `json:"teamId"`
+		Team         string       `json:"team"`
+		Game         string       `json:"game"`
+		Noncompeting bool         `json:"noncompeting"`
+		Score        float64      `json:"score"`
+		Groups       []groupScore `json:"groups"`
+	}
+
+	entries := []entry{}
+	for _, e := range board.Entries {
+		groups := []groupScore{}
+		for _, score := range e.GroupScores {
+			groups = append(groups, groupScore{Group: score.Name, Votes: score.Count, Score: score.Score})
+		}
+		entries = append(entries, entry{
+			Place:        e.Place,
+			SharedPlace:  e.SharedPlace,
+			TeamID:       e.Team.ID,
+			Team:         e.Team.Name,
+			Game:         e.Team.Game.Name,
+			Noncompeting: e.Team.Game.Noncompeting,
+			Score:        e.Score,
+			Groups:       groups,
+		})
+	}
+
+	context.Response.Header().Set("Content-Type", "application/json")
+	context.Response.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%v-%v.json", context.Event.ID, board.Aspect)))
+
+	encoder := json.NewEncoder(context.Response)
+	encoder.SetIndent("", "\t")
+	_ = encoder.Encode(struct {
+		Event   EventID `json:"event"`
+		Aspect  string  `json:"aspect"`
+		Entries []entry `json:"entries"`
+	}{context.Event.ID, board.Aspect, entries})
+}
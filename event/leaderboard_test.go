@@ -0,0 +1,67 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func withGroupEnjoyment(name string, count int, score float64) resultOption {
+	return func(result *TeamResult) {
+		result.GroupAverages = append(result.GroupAverages, GroupAverage{
+			Name:    name,
+			Weight:  50,
+			Count:   count,
+			Average: Aspects{Enjoyment: Aspect{Score: score}},
+		})
+	}
+}
+
+func TestNewLeaderboard(t *testing.T) {
+	event := &Event{Groups: []VoterGroup{{Name: "Judges", Weight: 50}}}
+	results := []*TeamResult{
+		rankedResult("A", 4, withEnjoyment(3), withGroupEnjoyment(DefaultGroupName, 2, 2.5), withGroupEnjoyment("Judges", 1, 3.5)),
+		rankedResult("Unscored", 0, withEnjoyment(5), withGroup("Guests", 0, 5)),
+		rankedResult("B", 3, withEnjoyment(4)),
+		rankedResult("Noncompeting", 5, withEnjoyment(5), asNoncompeting),
+		rankedResult("C", 2, withEnjoyment(3)),
+	}
+
+	board := NewLeaderboard("Enjoyment", results, event)
+
+	got := []string{}
+	for _, entry := range board.Entries {
+		switch {
+		case entry.Place == 0:
+			got = append(got, entry.Team.Name+"-")
+		case entry.SharedPlace:
+			got = append(got, fmt.Sprintf("%v#%v=", entry.Team.Name, entry.Place))
+		default:
+			got = append(got, fmt.Sprintf("%v#%v", entry.Team.Name, entry.Place))
+		}
+	}
+	expect := []string{"B#1", "A#2=", "C#2=", "Noncompeting-", "Unscored-"}
+	if !slices.Equal(got, expect) {
+		t.Errorf("got %v, expected %v", got, expect)
+	}
+
+	a := board.Entries[1]
+	expectScores := []GroupScore{
+		{Name: DefaultGroupName, Count: 2, Score: 2.5},
+		{Name: "Judges", Count: 1, Score: 3.5},
+	}
+	if !slices.Equal(a.GroupScores, expectScores) {
+		t.Errorf("group scores: got %v, expected %v", a.GroupScores, expectScores)
+	}
+	b := board.Entries[0]
+	if len(b.GroupScores) != 2 || b.GroupScores[1].Count != 0 {
+		t.Errorf("missing group scores: got %v", b.GroupScores)
+	}
+
+	// ranking by an aspect doesn't change the overall placements
+	for _, result := range results {
+		if result.Place != 0 {
+			t.Errorf("%v: overall place changed to %v", result.Team.Name, result.Place)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package event
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// MaxLinks is the maximum number of links on a game.
+const MaxLinks = 10
+
+// LinkType is the kind of page a game link points to.
+type LinkType string
+
+// Supported link types.
+const (
+	LinkItch     LinkType = "itch"
+	LinkDownload LinkType = "download"
+	LinkWeb      LinkType = "web"
+	LinkVideo    LinkType = "video"
+	LinkSource   LinkType = "source"
+	LinkSocial   LinkType = "social"
+	LinkOther    LinkType = "other"
+)
+
+// LinkTypes contains all supported link types.
+var LinkTypes = []LinkType{
+	LinkItch,
+	LinkDownload,
+	LinkWeb,
+	LinkVideo,
+	LinkSource,
+	LinkSocial,
+	LinkOther,
+}
+
+// videoHosts are sites accepted for video links.
+var videoHosts = []string{
+	"youtube.com", "youtu.be",
+	"vimeo.com",
+	"twitch.tv",
+	"streamable.com",
+	"drive.google.com",
+}
+
+// videoExtensions are video files accepted for video links.
+var videoExtensions = []string{".mp4", ".webm", ".mov", ".mkv"}
+
+// Valid checks whether kind is one of LinkTypes.
+func (kind LinkType) Valid() bool {
+	return slices.Contains(LinkTypes, kind)
+}
+
+// Title returns a human readable name of the link type.
+func (kind LinkType) Title() string {
+	switch kind {
+	case LinkItch:
+		return "itch.io"
+	case LinkDownload:
+		return "Download"
+	case LinkWeb:
+		return "Web Build"
+	case LinkVideo:
+		return "Video"
+	case LinkSource:
+		return "Source Code"
+	case LinkSocial:
+		return "Social Post"
+	}
+	return "Link"
+}
+
+// Icon returns a symbol shown next to links of the type.
+func (kind LinkType) Icon() string {
+	switch kind {
+	case LinkItch:
+		return "🕹️"
+	case LinkDownload:
+		return "📦"
+	case LinkWeb:
+		return "🌐"
+	case LinkVideo:
+		return "🎬"
+	case LinkSource:
+		return "💻"
+	case LinkSocial:
+		return "💬"
+	}
+	return "🔗"
+}
+
+// Link is a typed link to the game.
+type Link struct {
+	Type LinkType
+	URL  string
+}
+
+// Empty returns whether the link has no URL.
+func (link Link) Empty() bool { return link.URL == "" }
+
+// TypeOptions returns all link types for editing the link.
+func (link Link) TypeOptions() []LinkType { return LinkTypes }
+
+// Verify checks whether the URL is valid for the link type.
+func (link Link) Verify() error {
+	if !link.Type.Valid() {
+		return fmt.Errorf("unknown link type %q", link.Type)
+	}
+	title := link.Type.Title()
+
+	u, err := url.Parse(link.URL)
+	if err != nil {
+		return fmt.Errorf("invalid %v link: %w", title, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %v link %q, must start with http:// or https://", title, link.URL)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	switch link.Type {
+	case LinkItch:
+		if !hostMatches(host, "itch.io") {
+			return fmt.Errorf("%v link must point to itch.io", title)
+		}
+	case LinkWeb:
+		if u.Scheme != "https" {
+			return fmt.Errorf("%v link must use https", title)
+		}
+	case LinkVideo:
+		hosted := slices.ContainsFunc(videoHosts, func(site string) bool {
+			return hostMatches(host, site)
+		})
+		file := slices.Contains(videoExtensions, strings.ToLower(path.Ext(u.Path)))
+		if !hosted && !file {
+			return fmt.Errorf("%v link must point to a video site or a video file, use %v for other pages", title, LinkOther.Title())
+		}
+	case LinkSource:
+		if strings.Trim(u.Path, "/") == "" {
+			return fmt.Errorf("%v link must point to a repository", title)
+		}
+	}
+	return nil
+}
+
+// hostMatches checks whether host is site or its subdomain.
+func hostMatches(host, site string) bool {
+	return host == site || strings.HasSuffix(host, "."+site)
+}
+
+// verifyLinks checks all links of a game.
+func verifyLinks(links []Link) error {
+	if len(links) > MaxLinks {
+		return fmt.Errorf("game can have at most %v links", MaxLinks)
+	}
+	for _, link := range links {
+		if err := link.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GameLinks returns all links of the game.
+//
+// Games created before typed links get their links from LegacyLink.
+func (game *Game) GameLinks() []Link {
+	if len(game.Links) > 0 {
+		return game.Links
+	}
+
+	links := []Link{}
+	if jam := game.LegacyLink.Jam; jam != "" {
+		kind := LinkOther
+		if u, err := url.Parse(jam); err == nil && hostMatches(strings.ToLower(u.Hostname()), "itch.io") {
+			kind = LinkItch
+		}
+		links = append(links, Link{Type: kind, URL: jam})
+	}
+	if download := game.LegacyLink.Download; download != "" {
+		links = append(links, Link{Type: LinkDownload, URL: download})
+	}
+	if facebook := game.LegacyLink.Facebook; facebook != "" {
+		links = append(links, Link{Type: LinkSocial, URL: facebook})
+	}
+	return links
+}
+
+// LinksOfType returns links of the game with the specified type.
+func (game *Game) LinksOfType(kind LinkType) []Link {
+	links := []Link{}
+	for _, link := range game.GameLinks() {
+		if link.Type == kind {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// LinksForEdit returns links with additional empty links for adding.
+func (game *Game) LinksForEdit() []Link {
+	links := append([]Link{}, game.GameLinks()...)
+	for i := 0; i < 3 && len(links) < MaxLinks; i++ {
+		links = append(links, Link{})
+	}
+	return links
+}
+
+// HasLegacyLinks returns whether the game has links that haven't been migrated.
+func (game *Game) HasLegacyLinks() bool {
+	legacy := game.LegacyLink
+	return legacy.Jam != "" || legacy.Download != "" || legacy.Facebook != ""
+}
+
+// migrateLinks converts LegacyLink to Links.
+func (game *Game) migrateLinks() {
+	game.Links = game.GameLinks()
+	game.LegacyLink.Jam = ""
+	game.LegacyLink.Download = ""
+	game.LegacyLink.Facebook = ""
+}
+
+// MigrateLinks stores legacy links of all teams as typed links.
+func (server *Server) MigrateLinks(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to migrate links.")
+		context.Redirect("/", http.StatusSeeOther)
+		return
+	}
+	back := context.Event.Path("teams")
+	if context.Request.Method != http.MethodPost {
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashError(fmt.Sprintf("Unable to get teams: %v", err))
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+
+	migrated := 0
+	for _, team := range teams {
+		if !team.Game.HasLegacyLinks() {
+			continue
+		}
+		team.Game.migrateLinks()
+		if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+			context.FlashError(fmt.Sprintf("Unable to update team %q: %v", team.Name, err))
+			continue
+		}
+		migrated++
+	}
+
+	context.FlashMessage(fmt.Sprintf("Migrated links of %v teams.", migrated))
+	context.Redirect(back, http.StatusSeeOther)
+}
+
+// SubmissionRules decide when a team has submitted their game.
+type SubmissionRules struct {
+	// LinkTypes are link types that count as a submission,
+	// when empty any link is accepted.
+	LinkTypes []LinkType
+}
+
+// Options returns all link types for configuring the rules.
+func (rules SubmissionRules) Options() []LinkType { return LinkTypes }
+
+// Includes checks whether kind is explicitly listed in the rules.
+func (rules SubmissionRules) Includes(kind LinkType) bool {
+	return slices.Contains(rules.LinkTypes, kind)
+}
+
+// Accepts checks whether links of kind count as a submission.
+func (rules SubmissionRules) Accepts(kind LinkType) bool {
+	return len(rules.LinkTypes) == 0 || slices.Contains(rules.LinkTypes, kind)
+}
+
+// Submitted checks whether game has a link accepted by the rules.
+//
+// The hosted build counts as a web build.
+func (rules SubmissionRules) Submitted(game *Game) bool {
+	if !game.Build.Empty() && rules.Accepts(LinkWeb) {
+		return true
+	}
+	for _, link := range game.GameLinks() {
+		if rules.Accepts(link.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks that all link types are known.
+func (rules SubmissionRules) Verify() error {
+	for _, kind := range rules.LinkTypes {
+		if !kind.Valid() {
+			return fmt.Errorf("unknown link type %q", kind)
+		}
+	}
+	return nil
+}
+
+// parseLinks reads game links from the team form, skipping empty rows.
+func parseLinks(context *Context) []Link {
+	links := []Link{}
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("Team.Game.Links[%d].", i)
+		if _, ok := context.Request.Form[prefix+"URL"]; !ok {
+			break
+		}
+		link := Link{
+			Type: LinkType(context.FormValue(prefix + "Type")),
+			URL:  strings.TrimSpace(context.FormValue(prefix + "URL")),
+		}
+		if !link.Empty() {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// parseSubmissionRules reads submission rules from the event form.
+func parseSubmissionRules(context *Context) (SubmissionRules, error) {
+	rules := SubmissionRules{}
+	for _, kind := range context.Request.Form["Submission.LinkTypes"] {
+		rules.LinkTypes = append(rules.LinkTypes, LinkType(kind))
+	}
+	return rules, rules.Verify()
+}
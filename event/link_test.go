@@ -0,0 +1,105 @@
+package event
+
+import "testing"
+
+func TestLinkVerify(t *testing.T) {
+	tests := []struct {
+		link  Link
+		valid bool
+	}{
+		{Link{LinkItch, "https://team.itch.io/game"}, true},
+		{Link{LinkItch, "https://example.com/game"}, false},
+		{Link{LinkDownload, "https://example.com/game.zip"}, true},
+		{Link{LinkDownload, "ftp://example.com/game.zip"}, false},
+		{Link{LinkDownload, "example.com/game.zip"}, false},
+		{Link{LinkWeb, "https://example.com/play"}, true},
+		{Link{LinkWeb, "http://example.com/play"}, false},
+		{Link{LinkVideo, "https://www.youtube.com/watch?v=x"}, true},
+		{Link{LinkVideo, "https://cdn.example.com/trailer.mp4"}, true},
+		{Link{LinkVideo, "https://example.com/trailer"}, false},
+		{Link{LinkSource, "https://github.com/team/game"}, true},
+		{Link{LinkSource, "https://github.com/"}, false},
+		{Link{LinkSocial, "https://facebook.com/post"}, true},
+		{Link{LinkOther, "https://example.com"}, true},
+		{Link{"store", "https://example.com"}, false},
+	}
+	for _, test := range tests {
+		err := test.link.Verify()
+		if (err == nil) != test.valid {
+			t.Errorf("%v %q: got %v, expected valid=%v", test.link.Type, test.link.URL, err, test.valid)
+		}
+	}
+}
+
+func TestLegacyLinks(t *testing.T) {
+	game := &Game{}
+	game.LegacyLink.Jam = "https://jam.itch.io/game"
+	game.LegacyLink.Download = "https://example.com/game.zip"
+	game.LegacyLink.Facebook = "https://facebook.com/post"
+
+	expected := []Link{
+		{LinkItch, "https://jam.itch.io/game"},
+		{LinkDownload, "https://example.com/game.zip"},
+		{LinkSocial, "https://facebook.com/post"},
+	}
+	check := func(links []Link) {
+		t.Helper()
+		if len(links) != len(expected) {
+			t.Fatalf("got %v, expected %v", links, expected)
+		}
+		for i := range links {
+			if links[i] != expected[i] {
+				t.Errorf("got %v, expected %v", links[i], expected[i])
+			}
+		}
+	}
+	check(game.GameLinks())
+
+	game.migrateLinks()
+	check(game.Links)
+	if game.LegacyLink.Jam != "" || game.LegacyLink.Download != "" || game.LegacyLink.Facebook != "" {
+		t.Errorf("legacy links were not cleared: %v", game.LegacyLink)
+	}
+	check(game.GameLinks())
+}
+
+func TestSubmissionRules(t *testing.T) {
+	video := &Game{Links: []Link{{LinkVideo, "https://youtu.be/x"}}}
+	build := &Game{Build: Build{Version: 1, Files: 1}}
+
+	tests := []struct {
+		name      string
+		rules     SubmissionRules
+		game      *Game
+		submitted bool
+	}{
+		{"no links", SubmissionRules{}, &Game{}, false},
+		{"any link", SubmissionRules{}, video, true},
+		{"type not accepted", SubmissionRules{LinkTypes: []LinkType{LinkItch, LinkDownload}}, video, false},
+		{"type accepted", SubmissionRules{LinkTypes: []LinkType{LinkVideo}}, video, true},
+		{"hosted build is web", SubmissionRules{LinkTypes: []LinkType{LinkWeb}}, build, true},
+		{"hosted build not accepted", SubmissionRules{LinkTypes: []LinkType{LinkItch}}, build, false},
+	}
+	for _, test := range tests {
+		if got := test.rules.Submitted(test.game); got != test.submitted {
+			t.Errorf("%v: got %v, expected %v", test.name, got, test.submitted)
+		}
+	}
+}
+
+func TestHasVideo(t *testing.T) {
+	tests := []struct {
+		name string
+		game *Game
+		has  bool
+	}{
+		{"nothing", &Game{Links: []Link{{LinkItch, "https://jam.itch.io/game"}}}, false},
+		{"platform", &Game{Platforms: []Platform{PlatformVideo}}, true},
+		{"trailer", &Game{Platforms: []Platform{PlatformWeb}, Links: []Link{{LinkWeb, "https://example.com/play"}, {LinkVideo, "https://youtu.be/x"}}}, false},
+	}
+	for _, test := range tests {
+		if got := test.game.HasVideo(); got != test.has {
+			t.Errorf("%v: got %v, expected %v", test.name, got, test.has)
+		}
+	}
+}
@@ -0,0 +1,468 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LinkStatusRepo stores results of checking game links.
+type LinkStatusRepo interface {
+	LinkStatuses(eventid EventID) ([]*LinkStatus, error)
+	UpdateLinkStatus(eventid EventID, status *LinkStatus) error
+	DeleteLinkStatus(eventid EventID, teamid TeamID) error
+}
+
+const (
+	// LinkCheckTimeout is the time limit for checking a single link.
+	LinkCheckTimeout = 15 * time.Second
+	// MaxLinkRedirects is the number of redirects followed.
+	MaxLinkRedirects = 10
+	// linkCheckWorkers is the number of links checked in parallel.
+	linkCheckWorkers = 8
+)
+
+// loginHosts are sites that ask for signing in when a link is private.
+var loginHosts = []string{
+	"accounts.google.com",
+	"login.microsoftonline.com",
+	"login.live.com",
+}
+
+// LinkCheck is the result of checking a game link.
+type LinkCheck struct {
+	Link
+	Checked time.Time
+
+	Status      int
+	ContentType string
+	// Size is the reported content length, -1 when unknown.
+	Size int64
+	// Redirects are the locations the link redirected to, in order.
+	Redirects []string
+	// Problem describes why the link is broken, empty when it works.
+	Problem string
+}
+
+// Failed returns whether the link is broken.
+func (check *LinkCheck) Failed() bool { return check.Problem != "" }
+
+// SizeText returns the size in a human readable form.
+func (check *LinkCheck) SizeText() string {
+	switch {
+	case check.Size < 0:
+		return "unknown"
+	case check.Size < 1<<10:
+		return strconv.FormatInt(check.Size, 10) + "B"
+	case check.Size < 1<<20:
+		return strconv.FormatFloat(float64(check.Size)/(1<<10), 'f', 1, 64) + "KB"
+	}
+	return strconv.FormatFloat(float64(check.Size)/(1<<20), 'f', 1, 64) + "MB"
+}
+
+// LinkStatus contains the latest link checks of a team.
+type LinkStatus struct {
+	Team    TeamID
+	Checked time.Time   `datastore:",noindex"`
+	Checks  []LinkCheck `datastore:",noindex"`
+}
+
+// Failures returns checks of broken links.
+func (status *LinkStatus) Failures() []LinkCheck {
+	failures := []LinkCheck{}
+	if status == nil {
+		return failures
+	}
+	for _, check := range status.Checks {
+		if check.Failed() {
+			failures = append(failures, check)
+		}
+	}
+	return failures
+}
+
+// HasFailures returns whether any of the links are broken.
+func (status *LinkStatus) HasFailures() bool {
+	return len(status.Failures()) > 0
+}
+
+// Check returns the check of link, nil when it hasn't been checked.
+func (status *LinkStatus) Check(link Link) *LinkCheck {
+	if status == nil {
+		return nil
+	}
+	for i := range status.Checks {
+		if status.Checks[i].Link == link {
+			return &status.Checks[i]
+		}
+	}
+	return nil
+}
+
+// newFailures returns checks that failed and were working or unchecked previously.
+func (status *LinkStatus) newFailures(previous *LinkStatus) []LinkCheck {
+	failures := []LinkCheck{}
+	for _, check := range status.Failures() {
+		if before := previous.Check(check.Link); before != nil && before.Failed() {
+			continue
+		}
+		failures = append(failures, check)
+	}
+	return failures
+}
+
+// LinkChecker checks whether game links are reachable.
+type LinkChecker struct {
+	Client *http.Client
+	// UserAgent identifies the checker to the linked sites.
+	UserAgent string
+}
+
+// errPrivateAddress is returned when a link points to a non-public address.
+var errPrivateAddress = errors.New("link points to a private address")
+
+// NewLinkChecker returns a checker which only connects to public addresses.
+func NewLinkChecker() *LinkChecker {
+	dialer := &net.Dialer{
+		Timeout: LinkCheckTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &LinkChecker{
+		Client:    &http.Client{Transport: transport},
+		UserAgent: "jamvote-linkcheck/1.0",
+	}
+}
+
+// Check requests link and records the response.
+//
+// HEAD is tried first, GET is used when the site rejects HEAD.
+func (checker *LinkChecker) Check(ctx context.Context, link Link) LinkCheck {
+	check := LinkCheck{
+		Link:    link,
+		Checked: time.Now().UTC(),
+		Size:    -1,
+	}
+
+	resp, redirects, err := checker.request(ctx, http.MethodHead, link.URL)
+	if err != nil || resp.StatusCode >= 400 {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		resp, redirects, err = checker.request(ctx, http.MethodGet, link.URL)
+	}
+	check.Redirects = redirects
+	if err != nil {
+		check.Problem = "unreachable: " + linkError(err)
+		return check
+	}
+	defer resp.Body.Close()
+
+	check.Status = resp.StatusCode
+	check.ContentType = resp.Header.Get("Content-Type")
+	check.Size = resp.ContentLength
+
+	switch {
+	case resp.StatusCode >= 400:
+		check.Problem = fmt.Sprintf("responded with %v %v", resp.StatusCode, http.StatusText(resp.StatusCode))
+	case requiresLogin(resp.Request.URL):
+		check.Problem = "redirects to a login page, the link may be private"
+	}
+	return check
+}
+
+// request sends a single request following redirects.
+func (checker *LinkChecker) request(ctx context.Context, method, location string) (*http.Response, []string, error) {
+	ctx, cancel := context.WithTimeout(ctx, LinkCheckTimeout)
+
+	redirects := []string{}
+	client := http.Client{}
+	if checker.Client != nil {
+		client = *checker.Client
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > MaxLinkRedirects {
+			return fmt.Errorf("more than %v redirects", MaxLinkRedirects)
+		}
+		redirects = append(redirects, req.URL.String())
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, location, nil)
+	if err != nil {
+		cancel()
+		return nil, redirects, err
+	}
+	if checker.UserAgent != "" {
+		req.Header.Set("User-Agent", checker.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, redirects, err
+	}
+	resp.Body = cancelOnClose{resp.Body, cancel}
+	return resp, redirects, nil
+}
+
+// cancelOnClose releases the request context when the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the context.
+func (body cancelOnClose) Close() error {
+	err := body.ReadCloser.Close()
+	body.cancel()
+	return err
+}
+
+// linkError returns a short description of a request error.
+func linkError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timed out"
+	}
+	return err.Error()
+}
+
+// requiresLogin checks whether u is a sign in page.
+func requiresLogin(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if slices.Contains(loginHosts, host) {
+		return true
+	}
+	page := strings.ToLower(strings.TrimSuffix(u.Path, "/"))
+	return page == "/login" || page == "/signin"
+}
+
+// linkChecker returns the configured checker or a default one.
+func (server *Server) linkChecker() *LinkChecker {
+	if server.LinkChecker != nil {
+		return server.LinkChecker
+	}
+	return NewLinkChecker()
+}
+
+// CheckEventLinks checks links of all teams in an event,
+// organizers and teams are notified about links that became broken.
+func (server *Server) CheckEventLinks(ctx context.Context, eventid EventID) error {
+	repo := server.DB.Events(ctx)
+	teams, err := repo.Teams(eventid)
+	if err != nil {
+		return fmt.Errorf("unable to get teams: %w", err)
+	}
+	statuses, err := repo.LinkStatuses(eventid)
+	if err != nil {
+		return fmt.Errorf("unable to get link statuses: %w", err)
+	}
+	previous := map[TeamID]*LinkStatus{}
+	for _, status := range statuses {
+		previous[status.Team] = status
+	}
+
+	checker := server.linkChecker()
+	checked := make([]*LinkStatus, len(teams))
+	for i, team := range teams {
+		checked[i] = &LinkStatus{
+			Team:    team.ID,
+			Checked: time.Now().UTC(),
+			Checks:  make([]LinkCheck, len(team.Game.GameLinks())),
+		}
+	}
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, linkCheckWorkers)
+	for i, team := range teams {
+		for k, link := range team.Game.GameLinks() {
+			wg.Add(1)
+			limit <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-limit }()
+				checked[i].Checks[k] = checker.Check(ctx, link)
+			}()
+		}
+	}
+	wg.Wait()
+
+	type failure struct {
+		team  *Team
+		check LinkCheck
+	}
+	failures := []failure{}
+	for i, team := range teams {
+		status := checked[i]
+		if err := repo.UpdateLinkStatus(eventid, status); err != nil {
+			return fmt.Errorf("unable to update link status of %v: %w", team.Name, err)
+		}
+		broken := status.newFailures(previous[team.ID])
+		if len(broken) == 0 {
+			continue
+		}
+		for _, check := range broken {
+			failures = append(failures, failure{team: team, check: check})
+		}
+		if err := notifyBrokenLinks(repo, eventid, team.ID, broken); err != nil {
+			return fmt.Errorf("unable to notify %v: %w", team.Name, err)
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	// the event is loaded last to avoid overwriting changes made during checking
+	event, err := repo.ByID(eventid)
+	if err != nil {
+		return fmt.Errorf("unable to get event: %w", err)
+	}
+	for _, failure := range failures {
+		event.Notify(
+			fmt.Sprintf("%v link of team %v is broken: %v", failure.check.Type.Title(), failure.team.Name, failure.check.Problem),
+			event.Path("team", failure.team.ID),
+		)
+	}
+	return repo.Update(event)
+}
+
+// notifyBrokenLinks adds notifications about broken links to a team.
+//
+// The team is loaded again to avoid overwriting changes made during checking.
+func notifyBrokenLinks(repo Repo, eventid EventID, teamid TeamID, broken []LinkCheck) error {
+	team, err := repo.TeamByID(eventid, teamid)
+	if err != nil {
+		return err
+	}
+	edit := (&Event{ID: eventid}).Path("team", teamid, "edit")
+	for _, check := range broken {
+		team.Notify(fmt.Sprintf("Game link %v (%v) is broken: %v", check.URL, check.Type.Title(), check.Problem), edit)
+	}
+	return repo.UpdateTeam(eventid, team)
+}
+
+// CheckOpenEventLinks checks links of all events that haven't closed.
+func (server *Server) CheckOpenEventLinks(ctx context.Context) error {
+	events, err := server.DB.Events(ctx).List()
+	if err != nil {
+		return fmt.Errorf("unable to list events: %w", err)
+	}
+	var errs []error
+	for _, event := range events {
+		if event.Closed {
+			continue
+		}
+		if err := server.CheckEventLinks(ctx, event.ID); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", event.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CronCheckLinks checks links of open events, it's called hourly by cron.yaml.
+//
+// App Engine removes the X-Appengine-Cron header from outside requests,
+// other callers must be admins.
+func (server *Server) CronCheckLinks(context *Context) {
+	if context.Request.Header.Get("X-Appengine-Cron") != "true" && !context.CurrentUser.IsAdmin() {
+		context.Error("Must be admin to check links.", http.StatusForbidden)
+		return
+	}
+	if err := server.CheckOpenEventLinks(context); err != nil {
+		server.Site.Log.Error("link check failed", "error", err)
+		context.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+	context.Response.WriteHeader(http.StatusNoContent)
+}
+
+// linkStatusByTeam returns link statuses of an event by team.
+func linkStatusByTeam(context *Context) map[TeamID]*LinkStatus {
+	statuses, err := context.Events.LinkStatuses(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get link statuses: %v", err))
+	}
+	byTeam := map[TeamID]*LinkStatus{}
+	for _, status := range statuses {
+		byTeam[status.Team] = status
+	}
+	return byTeam
+}
+
+// TeamLinkStatus is a team together with the status of its links.
+type TeamLinkStatus struct {
+	*Team
+	Status *LinkStatus
+}
+
+// LinkHealth shows link check results of all teams to organizers.
+//
+// Checking links is done within the request, links are checked in parallel
+// and the request takes at most LinkCheckTimeout for each batch of links.
+func (server *Server) LinkHealth(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to view link health.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	if context.Request.Method == http.MethodPost {
+		if context.FormValue("action") != "check" {
+			context.FlashError("Unknown link action.")
+		} else if err := server.CheckEventLinks(context, context.Event.ID); err != nil {
+			context.FlashError(err.Error())
+		} else {
+			context.FlashMessage("Links checked.")
+		}
+		context.Redirect(context.Event.Path("links"), http.StatusSeeOther)
+		return
+	}
+
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get teams: %v", err))
+	}
+	statuses := linkStatusByTeam(context)
+
+	rows := []TeamLinkStatus{}
+	for _, team := range teams {
+		rows = append(rows, TeamLinkStatus{Team: team, Status: statuses[team.ID]})
+	}
+	sort.SliceStable(rows, func(i, k int) bool {
+		a, b := rows[i].Status.HasFailures(), rows[k].Status.HasFailures()
+		if a != b {
+			return a
+		}
+		return rows[i].Team.Less(rows[k].Team)
+	})
+
+	context.Data["Teams"] = rows
+	context.Render("event-links")
+}
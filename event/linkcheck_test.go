@@ -0,0 +1,104 @@
+package event
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLinkChecker(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/game.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Length", "2048")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(make([]byte, 2048))
+		}
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/game.zip", http.StatusFound)
+	})
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login?next=/private", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checker := &LinkChecker{Client: server.Client()}
+	check := func(path string) LinkCheck {
+		return checker.Check(context.Background(), Link{Type: LinkDownload, URL: server.URL + path})
+	}
+
+	ok := check("/game.zip")
+	if ok.Failed() || ok.Status != 200 || ok.ContentType != "application/zip" || ok.Size != 2048 || len(ok.Redirects) != 0 {
+		t.Errorf("game.zip: got %+v", ok)
+	}
+	if got := ok.SizeText(); got != "2.0KB" {
+		t.Errorf("size: got %q", got)
+	}
+
+	noHead := check("/no-head")
+	if noHead.Failed() || noHead.Status != 200 || noHead.ContentType != "text/html" {
+		t.Errorf("no-head: got %+v", noHead)
+	}
+
+	missing := check("/missing")
+	if !missing.Failed() || missing.Status != 404 || !strings.Contains(missing.Problem, "404") {
+		t.Errorf("missing: got %+v", missing)
+	}
+
+	moved := check("/moved")
+	if moved.Failed() || len(moved.Redirects) != 1 || moved.Redirects[0] != server.URL+"/game.zip" {
+		t.Errorf("moved: got %+v", moved)
+	}
+
+	private := check("/private")
+	if !private.Failed() || !strings.Contains(private.Problem, "login") {
+		t.Errorf("private: got %+v", private)
+	}
+
+	loop := check("/loop")
+	if !loop.Failed() || !strings.Contains(loop.Problem, "redirects") {
+		t.Errorf("loop: got %+v", loop)
+	}
+
+	local := NewLinkChecker().Check(context.Background(), Link{Type: LinkDownload, URL: server.URL + "/game.zip"})
+	if !local.Failed() || !strings.Contains(local.Problem, "private address") {
+		t.Errorf("local address: got %+v", local)
+	}
+}
+
+func TestLinkStatusNewFailures(t *testing.T) {
+	working := LinkCheck{Link: Link{LinkItch, "https://a.itch.io/game"}}
+	broken := LinkCheck{Link: Link{LinkDownload, "https://example.com/game.zip"}, Problem: "responded with 404 Not Found"}
+
+	current := &LinkStatus{Checks: []LinkCheck{working, broken}}
+	if failures := current.newFailures(nil); len(failures) != 1 || failures[0].Link != broken.Link {
+		t.Errorf("unchecked: got %v", failures)
+	}
+
+	fixed := broken
+	fixed.Problem = ""
+	if failures := current.newFailures(&LinkStatus{Checks: []LinkCheck{working, fixed}}); len(failures) != 1 {
+		t.Errorf("previously working: got %v", failures)
+	}
+	if failures := current.newFailures(&LinkStatus{Checks: []LinkCheck{working, broken}}); len(failures) != 0 {
+		t.Errorf("previously broken: got %v", failures)
+	}
+}
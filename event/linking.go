@@ -0,0 +1,220 @@
+package event
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/adinfinit/jamvote/internal/fuzzy"
+	"github.com/adinfinit/jamvote/user"
+)
+
+const (
+	// MinLinkConfidence is the minimum similarity for suggesting a user.
+	MinLinkConfidence = 0.6
+	// DefaultBulkLinkConfidence is the default threshold for accepting suggestions in bulk.
+	DefaultBulkLinkConfidence = 0.9
+	// maxLinkCandidates is the number of suggestions shown per member.
+	maxLinkCandidates = 3
+)
+
+// LinkCandidate is a user suggested for an unregistered member.
+type LinkCandidate struct {
+	User       *user.User
+	Confidence float64
+}
+
+// Percent returns the confidence as a percentage.
+func (candidate LinkCandidate) Percent() int {
+	return int(candidate.Confidence*100 + 0.5)
+}
+
+// MemberLink contains suggested users for an unregistered member.
+type MemberLink struct {
+	Member     Member
+	Candidates []LinkCandidate
+}
+
+// Best returns the suggestion to accept in bulk above threshold.
+//
+// Ambiguous suggestions, where the runner-up is equally confident, are skipped.
+func (link *MemberLink) Best(threshold float64) (LinkCandidate, bool) {
+	if len(link.Candidates) == 0 {
+		return LinkCandidate{}, false
+	}
+	best := link.Candidates[0]
+	if best.Confidence < threshold {
+		return LinkCandidate{}, false
+	}
+	if len(link.Candidates) > 1 && link.Candidates[1].Confidence >= best.Confidence {
+		return LinkCandidate{}, false
+	}
+	return best, true
+}
+
+// SuggestUsers finds users whose name is similar to the unregistered member of team.
+func SuggestUsers(team *Team, member Member, users []*user.User) []LinkCandidate {
+	candidates := []LinkCandidate{}
+	for _, u := range users {
+		if team.HasMember(u) {
+			continue
+		}
+		confidence := fuzzy.Similarity(member.Name, u.Name)
+		if confidence >= MinLinkConfidence {
+			candidates = append(candidates, LinkCandidate{User: u, Confidence: confidence})
+		}
+	}
+	sort.SliceStable(candidates, func(i, k int) bool {
+		return candidates[i].Confidence > candidates[k].Confidence
+	})
+	if len(candidates) > maxLinkCandidates {
+		candidates = candidates[:maxLinkCandidates]
+	}
+	return candidates
+}
+
+// LinkMember binds the unregistered member name to u.
+func (team *Team) LinkMember(name string, u *user.User) error {
+	if team.HasMember(u) {
+		return fmt.Errorf("%v is already a member of %v", u.Name, team.Name)
+	}
+	index, ok := team.unregisteredMember(name)
+	if !ok {
+		return fmt.Errorf("%q is not an unregistered member of %v", name, team.Name)
+	}
+	team.Members[index] = Member{ID: u.ID, Name: u.Name}
+	return nil
+}
+
+// parseLinkThreshold parses the bulk link threshold given in percent.
+func parseLinkThreshold(value string) (float64, error) {
+	if value == "" {
+		return DefaultBulkLinkConfidence, nil
+	}
+	percent, err := strconv.Atoi(value)
+	if err != nil || percent < int(MinLinkConfidence*100) || percent > 100 {
+		return 0, fmt.Errorf("threshold must be between %v%% and 100%%", int(MinLinkConfidence*100))
+	}
+	return float64(percent) / 100, nil
+}
+
+// LinkingAccept links an unregistered member to a suggested user.
+func (server *Server) LinkingAccept(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to link members.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+	if context.Request.Method != http.MethodPost {
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+
+	teamid, errTeam := strconv.ParseInt(context.FormValue("team"), 10, 64)
+	userid, errUser := strconv.ParseInt(context.FormValue("user"), 10, 64)
+	if errTeam != nil || errUser != nil {
+		context.FlashError("Invalid team or user.")
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+
+	team, err := context.Events.TeamByID(context.Event.ID, TeamID(teamid))
+	if err != nil {
+		context.FlashError(fmt.Sprintf("Unable to get team: %v", err))
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+	u, err := context.Users.ByID(user.UserID(userid))
+	if err != nil {
+		context.FlashError(fmt.Sprintf("Unable to get user: %v", err))
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+
+	member := context.FormValue("member")
+	if err := server.canJoinTeam(context, team, u); err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+	if err := team.LinkMember(member, u); err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+	if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+		context.FlashError(fmt.Sprintf("Unable to update team: %v", err))
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+
+	context.FlashMessage(fmt.Sprintf("Linked %q to %v.", member, u.Name))
+	context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+}
+
+// LinkingAcceptAll links all unregistered members whose best suggestion is above a threshold.
+func (server *Server) LinkingAcceptAll(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to link members.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+	if context.Request.Method != http.MethodPost {
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+
+	threshold, err := parseLinkThreshold(context.FormValue("threshold"))
+	if err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashError(fmt.Sprintf("Unable to get users: %v", err))
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashError(fmt.Sprintf("Unable to get teams: %v", err))
+		context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+		return
+	}
+
+	linked := 0
+	for _, team := range teams {
+		changed := false
+		for _, member := range append([]Member{}, team.Members...) {
+			if member.ID != 0 {
+				continue
+			}
+			link := MemberLink{Member: member, Candidates: SuggestUsers(team, member, users)}
+			best, ok := link.Best(threshold)
+			if !ok {
+				continue
+			}
+			if err := context.Event.TeamRules.CanJoin(team, best.User, teams); err != nil {
+				continue
+			}
+			if err := team.LinkMember(member.Name, best.User); err == nil {
+				changed = true
+				linked++
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+			context.FlashError(fmt.Sprintf("Unable to update team: %v", err))
+			context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+			return
+		}
+	}
+
+	context.FlashMessage(fmt.Sprintf("Linked %v members.", linked))
+	context.Redirect(context.Event.Path("linking"), http.StatusSeeOther)
+}
@@ -0,0 +1,461 @@
+package event
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// ModerationRepo is used to manage comment moderation for an event.
+type ModerationRepo interface {
+	Moderations(eventid EventID) ([]*Moderation, error)
+	UpdateModeration(eventid EventID, moderation *Moderation) error
+}
+
+// ModerationState is the review state of a ballot comment.
+type ModerationState string
+
+const (
+	// ModerationUnreviewed is a comment that no-one has looked at.
+	ModerationUnreviewed ModerationState = ""
+	// ModerationFlagged is a comment waiting for review, it is not shown to the team.
+	ModerationFlagged ModerationState = "Flagged"
+	// ModerationReported is a comment reported by the team after reveal.
+	ModerationReported ModerationState = "Reported"
+	// ModerationApproved is a comment that was reviewed and is shown as is.
+	ModerationApproved ModerationState = "Approved"
+	// ModerationHidden is a comment that is not shown to the team.
+	ModerationHidden ModerationState = "Hidden"
+	// ModerationEdited is a comment that is shown with edited text.
+	ModerationEdited ModerationState = "Edited"
+)
+
+// ModerationStates contains states organizers can pick.
+var ModerationStates = []ModerationState{
+	ModerationFlagged,
+	ModerationApproved,
+	ModerationHidden,
+	ModerationEdited,
+}
+
+// Moderation is the review of a single ballot comment.
+type Moderation struct {
+	// Ref is the Ballot.Ref of the moderated ballot.
+	Ref    string
+	Aspect string
+	State  ModerationState `datastore:",noindex"`
+	Reason string          `datastore:",noindex"`
+
+	// Original is the comment at the time of moderation.
+	// When the voter changes the comment, the moderation no longer applies.
+	Original string `datastore:",noindex"`
+	// Edited is the comment shown instead of Original.
+	Edited string `datastore:",noindex"`
+
+	Moderator user.UserID `datastore:",noindex"`
+	Time      time.Time   `datastore:",noindex"`
+
+	// Report is the reason given by the team for reporting the comment.
+	Report     string      `datastore:",noindex"`
+	ReportedBy user.UserID `datastore:",noindex"`
+	Reported   time.Time   `datastore:",noindex"`
+}
+
+// NewBallotRef returns a new random reference for a ballot.
+func NewBallotRef() string { return newToken() }
+
+// FlaggedWord returns the first flagged word contained in comment.
+func (event *Event) FlaggedWord(comment string) (string, bool) {
+	if len(event.FlaggedWords) == 0 || comment == "" {
+		return "", false
+	}
+	words := strings.FieldsFunc(strings.ToLower(comment), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+	for _, flagged := range event.FlaggedWords {
+		flagged = strings.ToLower(flagged)
+		if strings.Contains(flagged, " ") {
+			if strings.Contains(strings.ToLower(comment), flagged) {
+				return flagged, true
+			}
+			continue
+		}
+		for _, word := range words {
+			if word == flagged {
+				return flagged, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseWordList splits words separated by newlines or commas.
+func parseWordList(text string) []string {
+	words := []string{}
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ',' }) {
+		word = strings.TrimSpace(word)
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// CommentReview is a single ballot comment with its moderation.
+type CommentReview struct {
+	Ballot *Ballot
+	Team   *Team
+	Aspect string
+	// Moderation is nil when the comment has not been moderated.
+	Moderation *Moderation
+	// FlaggedWord is the word from the event word list the comment contains.
+	FlaggedWord string
+}
+
+// Comment returns the comment written by the voter.
+func (review *CommentReview) Comment() string {
+	return review.Ballot.Aspects.Comment(review.Aspect)
+}
+
+// State returns the effective moderation state of the comment.
+func (review *CommentReview) State() ModerationState {
+	if review.Moderation != nil {
+		return review.Moderation.State
+	}
+	if review.FlaggedWord != "" {
+		return ModerationFlagged
+	}
+	return ModerationUnreviewed
+}
+
+// NeedsAttention returns whether an organizer should look at the comment.
+func (review *CommentReview) NeedsAttention() bool {
+	state := review.State()
+	return state == ModerationFlagged || state == ModerationReported
+}
+
+// Visible returns the comment shown to the team, empty when hidden.
+func (review *CommentReview) Visible() string {
+	switch review.State() {
+	case ModerationFlagged, ModerationHidden:
+		return ""
+	case ModerationEdited:
+		return review.Moderation.Edited
+	case ModerationReported:
+		if review.Moderation.Edited != "" {
+			return review.Moderation.Edited
+		}
+	}
+	return review.Comment()
+}
+
+// ReviewComments collects all comments from completed ballots with their moderation.
+//
+// Moderations whose original comment no longer matches the ballot are ignored.
+func ReviewComments(event *Event, ballots []*Ballot, teams []*Team, moderations []*Moderation) []*CommentReview {
+	teambyid := map[TeamID]*Team{}
+	for _, team := range teams {
+		teambyid[team.ID] = team
+	}
+	type key struct{ ref, aspect string }
+	moderationbykey := map[key]*Moderation{}
+	for _, moderation := range moderations {
+		moderationbykey[key{moderation.Ref, moderation.Aspect}] = moderation
+	}
+
+	reviews := []*CommentReview{}
+	for _, ballot := range ballots {
+		team, ok := teambyid[ballot.Team]
+		if !ok || !ballot.Completed {
+			continue
+		}
+		for _, aspect := range AspectNames {
+			comment := ballot.Aspects.Comment(aspect)
+			if comment == "" {
+				continue
+			}
+			review := &CommentReview{
+				Ballot: ballot,
+				Team:   team,
+				Aspect: aspect,
+			}
+			if ballot.Ref != "" {
+				moderation := moderationbykey[key{ballot.Ref, aspect}]
+				if moderation != nil && moderation.Original == comment {
+					review.Moderation = moderation
+				}
+			}
+			review.FlaggedWord, _ = event.FlaggedWord(comment)
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews
+}
+
+// ModeratedAspects returns ballot aspects with comments replaced by their visible text.
+//
+// Comments without a review, such as on ballots without a Ref, are hidden.
+func ModeratedAspects(ballot *Ballot, reviews []*CommentReview) Aspects {
+	aspects := ballot.Aspects
+	aspects.ClearComments()
+	for _, review := range reviews {
+		if review.Ballot != ballot {
+			continue
+		}
+		visible := review.Visible()
+		switch review.Aspect {
+		case "Theme":
+			aspects.Theme.Comment = visible
+		case "Enjoyment":
+			aspects.Enjoyment.Comment = visible
+		case "Aesthetics":
+			aspects.Aesthetics.Comment = visible
+		case "Innovation":
+			aspects.Innovation.Comment = visible
+		case "Bonus":
+			aspects.Bonus.Comment = visible
+		case "Overall":
+			aspects.Overall.Comment = visible
+		}
+	}
+	return aspects
+}
+
+// assignBallotRefs gives a Ref to completed ballots created before refs existed,
+// so that their comments can be moderated, reported and answered.
+func assignBallotRefs(context *Context, ballots []*Ballot) error {
+	missing := []*Ballot{}
+	for _, ballot := range ballots {
+		if ballot.Completed && ballot.Ref == "" {
+			missing = append(missing, ballot)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return context.Events.AssignBallotRefs(context.Event.ID, missing)
+}
+
+// teamCommentReviews loads comments for the current team.
+//
+// Ballots without a Ref are skipped until the Moderation page assigns them one,
+// since their comments cannot be moderated, reported or answered.
+func (server *Server) teamCommentReviews(context *Context, ballots []*Ballot) ([]*CommentReview, error) {
+	moderations, err := context.Events.Moderations(context.Event.ID)
+	if err != nil {
+		return nil, err
+	}
+	referenced := []*Ballot{}
+	for _, ballot := range ballots {
+		if ballot.Ref != "" {
+			referenced = append(referenced, ballot)
+		}
+	}
+	return ReviewComments(context.Event, referenced, []*Team{context.Team}, moderations), nil
+}
+
+// Moderation handles the comment moderation queue.
+func (server *Server) Moderation(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to moderate comments.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	ballots, err := context.Events.Ballots(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	if err := assignBallotRefs(context, ballots); err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	moderations, err := context.Events.Moderations(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	reviews := ReviewComments(context.Event, ballots, teams, moderations)
+
+	if context.Request.Method == http.MethodPost {
+		if err := context.Request.ParseForm(); err != nil {
+			context.FlashError("Parse form: " + err.Error())
+			context.Redirect(context.Event.Path("moderation"), http.StatusSeeOther)
+			return
+		}
+
+		ref := context.FormValue("Ref")
+		aspect := context.FormValue("Aspect")
+		var review *CommentReview
+		for _, r := range reviews {
+			if r.Ballot.Ref != "" && r.Ballot.Ref == ref && r.Aspect == aspect {
+				review = r
+				break
+			}
+		}
+		if review == nil {
+			context.FlashError("Comment does not exist.")
+			context.Redirect(context.Event.Path("moderation"), http.StatusSeeOther)
+			return
+		}
+
+		moderation := &Moderation{Ref: ref, Aspect: aspect}
+		if review.Moderation != nil {
+			*moderation = *review.Moderation
+		}
+		moderation.State = ModerationState(context.FormValue("State"))
+		moderation.Reason = context.FormValue("Reason")
+		moderation.Original = review.Comment()
+		moderation.Edited = ""
+		moderation.Moderator = context.CurrentUser.ID
+		moderation.Time = time.Now().UTC()
+
+		switch moderation.State {
+		case ModerationFlagged, ModerationApproved:
+		case ModerationHidden, ModerationEdited:
+			if moderation.Reason == "" {
+				context.FlashError(fmt.Sprintf("%v comments need a reason.", moderation.State))
+				context.Redirect(context.Event.Path("moderation"), http.StatusSeeOther)
+				return
+			}
+			if moderation.State == ModerationEdited {
+				moderation.Edited = context.FormValue("Edited")
+				if moderation.Edited == "" {
+					context.FlashError("Edited comment cannot be empty, hide it instead.")
+					context.Redirect(context.Event.Path("moderation"), http.StatusSeeOther)
+					return
+				}
+			}
+		default:
+			context.FlashError(fmt.Sprintf("Unknown moderation state %q.", moderation.State))
+			context.Redirect(context.Event.Path("moderation"), http.StatusSeeOther)
+			return
+		}
+
+		if err := context.Events.UpdateModeration(context.Event.ID, moderation); err != nil {
+			context.FlashError(err.Error())
+		} else {
+			context.FlashMessage(fmt.Sprintf("Comment for %v marked as %v.", review.Team.Name, moderation.State))
+		}
+		context.Redirect(context.Event.Path("moderation"), http.StatusSeeOther)
+		return
+	}
+
+	pending := []*CommentReview{}
+	unreviewed := []*CommentReview{}
+	moderated := []*CommentReview{}
+	for _, review := range reviews {
+		switch {
+		case review.NeedsAttention():
+			pending = append(pending, review)
+		case review.State() == ModerationUnreviewed:
+			unreviewed = append(unreviewed, review)
+		default:
+			moderated = append(moderated, review)
+		}
+	}
+	for _, list := range [][]*CommentReview{pending, unreviewed, moderated} {
+		sort.SliceStable(list, func(i, k int) bool {
+			return list[i].Team.Name < list[k].Team.Name
+		})
+	}
+
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	context.Data["UserNames"] = userNames(append(users, context.Event.PlaceholderUsers()...))
+	context.Data["States"] = ModerationStates
+	context.Data["Pending"] = pending
+	context.Data["Unreviewed"] = unreviewed
+	context.Data["Moderated"] = moderated
+	context.Render("event-moderation")
+}
+
+// ReportComment lets team members report a comment after reveal.
+func (server *Server) ReportComment(context *Context) {
+	if context.Team == nil {
+		teamid, _ := context.IntParam("teamid")
+		context.FlashError(fmt.Sprintf("Team %v does not exist", teamid))
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	if !context.CurrentUser.IsAdmin() && !context.Team.HasMember(context.CurrentUser) {
+		context.FlashError("Only team members can report comments.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	if !context.Event.Revealed {
+		context.FlashError("Comments can be reported after results are revealed.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	if err := context.Request.ParseForm(); err != nil {
+		context.FlashError("Parse form: " + err.Error())
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	ballots, err := context.Events.TeamBallots(context.Event.ID, context.Team.ID)
+	if err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+	reviews, err := server.teamCommentReviews(context, ballots)
+	if err != nil {
+		context.FlashError(err.Error())
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	ref := context.FormValue("Ref")
+	aspect := context.FormValue("Aspect")
+	var review *CommentReview
+	for _, r := range reviews {
+		if r.Ballot.Ref != "" && r.Ballot.Ref == ref && r.Aspect == aspect && r.Visible() != "" {
+			review = r
+			break
+		}
+	}
+	if review == nil {
+		context.FlashError("Comment does not exist.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	reason := context.FormValue("Reason")
+	if reason == "" {
+		context.FlashError("Please describe what is wrong with the comment.")
+		context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+		return
+	}
+
+	moderation := &Moderation{Ref: ref, Aspect: aspect, Original: review.Comment()}
+	if review.Moderation != nil {
+		*moderation = *review.Moderation
+	}
+	moderation.State = ModerationReported
+	moderation.Report = reason
+	moderation.ReportedBy = context.CurrentUser.ID
+	moderation.Reported = time.Now().UTC()
+
+	if err := context.Events.UpdateModeration(context.Event.ID, moderation); err != nil {
+		context.FlashError(err.Error())
+	} else {
+		context.FlashMessage("Comment reported, organizers will review it.")
+	}
+	context.Redirect(context.Event.Path("team", context.Team.ID), http.StatusSeeOther)
+}
@@ -0,0 +1,96 @@
+package event
+
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// MaxNotifications is the number of notifications kept per event or team.
+const MaxNotifications = 100
+
+// Notification is a message for event organizers or team members.
+type Notification struct {
+	Time    time.Time
+	Message string
+	// Link is an optional path with more details.
+	Link string
+}
+
+// Notify adds a notification for organizers, dropping the oldest ones over MaxNotifications.
+func (event *Event) Notify(message, link string) {
+	event.Notifications = appendNotification(event.Notifications, message, link)
+}
+
+// Notify adds a notification for team members, dropping the oldest ones over MaxNotifications.
+func (team *Team) Notify(message, link string) {
+	team.Notifications = appendNotification(team.Notifications, message, link)
+}
+
+// appendNotification adds a notification, dropping the oldest ones over MaxNotifications.
+func appendNotification(notifications []Notification, message, link string) []Notification {
+	notifications = append(notifications, Notification{
+		Time:    time.Now().UTC(),
+		Message: message,
+		Link:    link,
+	})
+	if extra := len(notifications) - MaxNotifications; extra > 0 {
+		notifications = slices.Delete(notifications, 0, extra)
+	}
+	return notifications
+}
+
+// Notifications lists organizer notifications, newest first.
+func (server *Server) Notifications(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to view notifications.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	if context.Request.Method == http.MethodPost {
+		if context.FormValue("action") != "clear" {
+			context.FlashError("Unknown notification action.")
+			context.Redirect(context.Event.Path("notifications"), http.StatusSeeOther)
+			return
+		}
+		context.Event.Notifications = nil
+		if err := context.Events.Update(context.Event); err != nil {
+			context.FlashError(err.Error())
+		} else {
+			context.FlashMessage("Notifications cleared.")
+		}
+		context.Redirect(context.Event.Path("notifications"), http.StatusSeeOther)
+		return
+	}
+
+	notifications := slices.Clone(context.Event.Notifications)
+	slices.Reverse(notifications)
+	context.Data["Notifications"] = notifications
+	context.Render("event-notifications")
+}
+
+// TeamNotifications clears notifications of a team.
+func (server *Server) TeamNotifications(context *Context) {
+	if !server.canEditTeam(context) {
+		return
+	}
+	back := context.Event.Path("team", context.Team.ID)
+	if context.Request.Method != http.MethodPost {
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+	if context.FormValue("action") != "clear" {
+		context.FlashError("Unknown notification action.")
+		context.Redirect(back, http.StatusSeeOther)
+		return
+	}
+
+	context.Team.Notifications = nil
+	if err := context.Events.UpdateTeam(context.Event.ID, context.Team); err != nil {
+		context.FlashError(err.Error())
+	} else {
+		context.FlashMessage("Notifications cleared.")
+	}
+	context.Redirect(back, http.StatusSeeOther)
+}
@@ -0,0 +1,157 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// Platform is a platform a game runs on.
+type Platform string
+
+// Supported platforms.
+const (
+	PlatformWeb     Platform = "Web"
+	PlatformWindows Platform = "Windows"
+	PlatformMacOS   Platform = "macOS"
+	PlatformLinux   Platform = "Linux"
+	PlatformAndroid Platform = "Android"
+	// PlatformVideo is a game that can only be watched as a video.
+	PlatformVideo Platform = "Video"
+)
+
+// Platforms contains all supported platforms.
+var Platforms = []Platform{
+	PlatformWeb,
+	PlatformWindows,
+	PlatformMacOS,
+	PlatformLinux,
+	PlatformAndroid,
+	PlatformVideo,
+}
+
+// universalPlatforms can be played by everyone.
+var universalPlatforms = []Platform{PlatformWeb, PlatformVideo}
+
+// Valid checks whether platform is one of Platforms.
+func (platform Platform) Valid() bool {
+	return slices.Contains(Platforms, platform)
+}
+
+// HasPlatform checks whether the game runs on platform.
+func (game *Game) HasPlatform(platform Platform) bool {
+	return slices.Contains(game.Platforms, platform)
+}
+
+// HasVideo checks whether the game has a video build.
+//
+// Video links are not enough, since playable games can link to a trailer.
+func (game *Game) HasVideo() bool {
+	return game.HasPlatform(PlatformVideo)
+}
+
+// PlayableOn checks whether the game can be played with any of platforms.
+//
+// Games and voters without platforms are assumed to be compatible,
+// web and video-only games can be played by everyone.
+func (game *Game) PlayableOn(platforms []string) bool {
+	if len(game.Platforms) == 0 || len(platforms) == 0 {
+		return true
+	}
+	for _, platform := range game.Platforms {
+		if slices.Contains(universalPlatforms, platform) {
+			return true
+		}
+		if slices.Contains(platforms, string(platform)) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyPlatforms checks that all platforms are known.
+func verifyPlatforms(platforms []Platform) error {
+	for _, platform := range platforms {
+		if !platform.Valid() {
+			return fmt.Errorf("unknown platform %q", platform)
+		}
+	}
+	return nil
+}
+
+// PlatformCount contains the number of games and voters for a platform.
+type PlatformCount struct {
+	Platform Platform
+	Games    int
+	Voters   int
+}
+
+// PlatformCoverage summarizes which platforms games need and voters have.
+type PlatformCoverage struct {
+	Platforms []PlatformCount
+	// Unspecified is the number of games without platforms.
+	Unspecified int
+	// Undeclared is the number of voters without platforms.
+	Undeclared int
+	// Unplayable are games that none of the voters can play.
+	Unplayable []*Team
+}
+
+// NewPlatformCoverage calculates platform coverage of teams and voters.
+func NewPlatformCoverage(teams []*Team, voters []*user.User) *PlatformCoverage {
+	coverage := &PlatformCoverage{}
+	for _, platform := range Platforms {
+		count := PlatformCount{Platform: platform}
+		for _, team := range teams {
+			if team.Game.HasPlatform(platform) {
+				count.Games++
+			}
+		}
+		for _, voter := range voters {
+			if slices.Contains(voter.Platforms, string(platform)) {
+				count.Voters++
+			}
+		}
+		coverage.Platforms = append(coverage.Platforms, count)
+	}
+
+	for _, team := range teams {
+		if len(team.Game.Platforms) == 0 {
+			coverage.Unspecified++
+			continue
+		}
+		playable := false
+		for _, voter := range voters {
+			if team.HasMemberID(voter.ID) {
+				continue
+			}
+			if team.Game.PlayableOn(voter.Platforms) {
+				playable = true
+				break
+			}
+		}
+		if !playable {
+			coverage.Unplayable = append(coverage.Unplayable, team)
+		}
+	}
+
+	for _, voter := range voters {
+		if len(voter.Platforms) == 0 {
+			coverage.Undeclared++
+		}
+	}
+
+	return coverage
+}
+
+// eventVoters returns users who have been approved for the event.
+func eventVoters(event *Event, users []*user.User) []*user.User {
+	voters := []*user.User{}
+	for _, u := range users {
+		if event.HasJammer(u) {
+			voters = append(voters, u)
+		}
+	}
+	return voters
+}
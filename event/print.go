@@ -0,0 +1,217 @@
+package event
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/adinfinit/jamvote/internal/qr"
+)
+
+// ScaleStep is a single checkbox on a printed aspect scale.
+type ScaleStep struct {
+	Value float64
+	Label string
+}
+
+// Scale returns the steps for a printed ballot, one for each option.
+//
+// The values match the labels shown while voting online.
+func (aspect AspectDescription) Scale() []ScaleStep {
+	steps := []ScaleStep{}
+	for i, option := range aspect.Options {
+		steps = append(steps, ScaleStep{
+			Value: aspect.Min + float64(i),
+			Label: option,
+		})
+	}
+	return steps
+}
+
+// PrintedTeam is a team with links for printed material.
+type PrintedTeam struct {
+	*Team
+	VoteURL string
+	VoteQR  template.HTML
+	TeamURL string
+	TeamQR  template.HTML
+}
+
+// siteDomain returns the configured site domain, empty when it isn't configured.
+//
+// The request host is not used, since it can be spoofed and doesn't
+// include the scheme seen by visitors behind a proxy.
+func (server *Server) siteDomain() string {
+	if server.Users == nil || server.Users.Auth == nil {
+		return ""
+	}
+	return strings.TrimSuffix(server.Users.Auth.Domain, "/")
+}
+
+// absoluteURL returns path prefixed with the site domain,
+// path is returned as is when the domain isn't configured.
+func (server *Server) absoluteURL(path string) string {
+	return server.siteDomain() + path
+}
+
+// canPrint checks whether printed links can be created, redirecting to back when not.
+func (server *Server) canPrint(context *Context, back string) bool {
+	if server.siteDomain() == "" {
+		context.FlashError("Printed links need the site domain, configure DOMAIN.")
+		context.Redirect(back, http.StatusSeeOther)
+		return false
+	}
+	return true
+}
+
+// qrSVG returns link as an inline QR code image.
+func qrSVG(link string) template.HTML {
+	code, err := qr.Encode(link)
+	if err != nil {
+		return ""
+	}
+	return template.HTML(code.SVG())
+}
+
+// printedTeams returns submitted teams that can be voted on, sorted by name.
+func (server *Server) printedTeams(context *Context) []*PrintedTeam {
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	sort.Slice(teams, func(i, k int) bool {
+		return teams[i].Less(teams[k])
+	})
+
+	printed := []*PrintedTeam{}
+	for _, team := range teams {
+		if !team.HasSubmitted(context.Event.Submission) {
+			continue
+		}
+		if team.Game.Noncompeting && context.Event.SkipNoncompeting {
+			continue
+		}
+		printed = append(printed, server.printedTeam(context, team))
+	}
+	return printed
+}
+
+// printedTeam creates links and QR codes for team.
+func (server *Server) printedTeam(context *Context, team *Team) *PrintedTeam {
+	voteURL := server.absoluteURL(context.Event.Path("vote", team.ID))
+	teamURL := server.absoluteURL(context.Event.Path("team", team.ID))
+	return &PrintedTeam{
+		Team:    team,
+		VoteURL: voteURL,
+		VoteQR:  qrSVG(voteURL),
+		TeamURL: teamURL,
+		TeamQR:  qrSVG(teamURL),
+	}
+}
+
+// BallotSheets displays printable paper ballots for every team and a judge summary sheet.
+func (server *Server) BallotSheets(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to print ballots.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	if !server.canPrint(context, context.Event.Path("proxy-ballot")) {
+		return
+	}
+
+	context.Data["Title"] = "Ballot Sheets"
+	context.Data["Back"] = context.Event.Path("proxy-ballot")
+	context.Data["Aspects"] = AspectDescriptions
+	context.Data["Teams"] = server.printedTeams(context)
+	context.Render("event-ballot-sheets")
+}
+
+// TeamQR serves a QR code image linking to the team vote or team page.
+func (server *Server) TeamQR(context *Context) {
+	if context.Team == nil {
+		teamid, _ := context.IntParam("teamid")
+		context.Error(fmt.Sprintf("Team %v does not exist.", teamid), http.StatusNotFound)
+		return
+	}
+
+	if server.siteDomain() == "" {
+		context.Error("Site domain is not configured.", http.StatusInternalServerError)
+		return
+	}
+
+	page, _ := context.StringParam("page")
+	var link string
+	switch page {
+	case "vote.png":
+		link = server.absoluteURL(context.Event.Path("vote", context.Team.ID))
+	case "team.png":
+		link = server.absoluteURL(context.Event.Path("team", context.Team.ID))
+	default:
+		context.Error(fmt.Sprintf("QR code %q does not exist.", page), http.StatusNotFound)
+		return
+	}
+
+	scale := 8
+	if value := context.FormValue("scale"); value != "" {
+		var err error
+		scale, err = strconv.Atoi(value)
+		if err != nil || scale < 1 || scale > 32 {
+			context.Error("Scale must be between 1 and 32.", http.StatusBadRequest)
+			return
+		}
+	}
+
+	code, err := qr.Encode(link)
+	if err != nil {
+		context.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	context.Response.Header().Set("Content-Type", "image/png")
+	context.Response.Header().Set("Cache-Control", "public, max-age=86400")
+	if err := code.PNG(context.Response, scale); err != nil {
+		context.Site.Log.Error("failed to encode QR code", "error", err)
+	}
+}
+
+// TableCard displays a printable card for the team's showcase table.
+func (server *Server) TableCard(context *Context) {
+	if context.Team == nil {
+		teamid, _ := context.IntParam("teamid")
+		context.FlashError(fmt.Sprintf("Team %v does not exist.", teamid))
+		context.Redirect(context.Event.Path("teams"), http.StatusSeeOther)
+		return
+	}
+
+	if !server.canPrint(context, context.Event.Path("team", context.Team.ID)) {
+		return
+	}
+
+	context.Data["Title"] = context.Team.Name + " Table Card"
+	context.Data["Back"] = context.Event.Path("team", context.Team.ID)
+	context.Data["Teams"] = []*PrintedTeam{server.printedTeam(context, context.Team)}
+	context.Render("event-table-cards")
+}
+
+// TableCards displays printable cards for all submitted teams.
+func (server *Server) TableCards(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to print table cards.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	if !server.canPrint(context, context.Event.Path("teams")) {
+		return
+	}
+
+	context.Data["Title"] = "Table Cards"
+	context.Data["Back"] = context.Event.Path("teams")
+	context.Data["Teams"] = server.printedTeams(context)
+	context.Render("event-table-cards")
+}
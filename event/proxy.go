@@ -0,0 +1,264 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// ErrBallotByVoter is returned when a proxy ballot would overwrite a ballot submitted by the voter.
+var ErrBallotByVoter = errors.New("the voter has already submitted this ballot themselves")
+
+// PlaceholderJudge is a voter without an account, whose ballots are entered by an admin.
+//
+// Placeholder judges use negative ids, so they never clash with user ids.
+type PlaceholderJudge struct {
+	ID   user.UserID
+	Name string
+}
+
+// Placeholder finds a placeholder judge by id.
+func (event *Event) Placeholder(userid user.UserID) (PlaceholderJudge, bool) {
+	for _, judge := range event.Placeholders {
+		if judge.ID == userid {
+			return judge, true
+		}
+	}
+	return PlaceholderJudge{}, false
+}
+
+// AddPlaceholder returns a placeholder judge with name, creating it when needed.
+func (event *Event) AddPlaceholder(name string) (PlaceholderJudge, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return PlaceholderJudge{}, errors.New("placeholder judge name cannot be empty")
+	}
+
+	next := user.UserID(-1)
+	for _, judge := range event.Placeholders {
+		if strings.EqualFold(judge.Name, name) {
+			return judge, nil
+		}
+		if judge.ID <= next {
+			next = judge.ID - 1
+		}
+	}
+
+	judge := PlaceholderJudge{ID: next, Name: name}
+	event.Placeholders = append(event.Placeholders, judge)
+	return judge, nil
+}
+
+// PlaceholderUsers returns placeholder judges as users for name lookups.
+func (event *Event) PlaceholderUsers() []*user.User {
+	users := []*user.User{}
+	for _, judge := range event.Placeholders {
+		users = append(users, &user.User{ID: judge.ID, Name: judge.Name})
+	}
+	return users
+}
+
+// checkProxyBallot checks whether admin can enter a ballot on behalf of the voter.
+//
+// Proxy ballots don't need to be assigned, since placeholder judges
+// and paper voters never fill a voting queue.
+func (event *Event) checkProxyBallot(voter user.UserID, team *Team, existing *Ballot) error {
+	if err := event.CheckAssignable(voter, team); err != nil {
+		return err
+	}
+	if existing != nil && existing.Completed && !existing.Proxy {
+		return ErrBallotByVoter
+	}
+	return nil
+}
+
+// ProxyBallot handles entering paper or proxy ballots on behalf of a voter.
+func (server *Server) ProxyBallot(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to enter ballots.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	sort.Slice(teams, func(i, k int) bool {
+		return teams[i].Less(teams[k])
+	})
+
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	voters := eventVoters(context.Event, users)
+	sort.Slice(voters, func(i, k int) bool {
+		return voters[i].Name < voters[k].Name
+	})
+
+	ballots, err := context.Events.Ballots(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	proxies := []*Ballot{}
+	for _, ballot := range ballots {
+		if ballot.Proxy {
+			proxies = append(proxies, ballot)
+		}
+	}
+
+	teamnames := map[TeamID]string{}
+	for _, team := range teams {
+		teamnames[team.ID] = team.Name
+	}
+
+	context.Data["Aspects"] = AspectDescriptions
+	context.Data["Teams"] = teams
+	context.Data["Voters"] = voters
+	context.Data["Proxies"] = proxies
+	context.Data["TeamNames"] = teamnames
+	context.Data["UserNames"] = userNames(append(users, context.Event.PlaceholderUsers()...))
+
+	if context.Request.Method != http.MethodPost {
+		context.Render("event-proxy-ballot")
+		return
+	}
+
+	fail := func(message string) {
+		context.FlashErrorNow(message)
+		context.Response.WriteHeader(http.StatusBadRequest)
+		context.Render("event-proxy-ballot")
+	}
+
+	if !context.Event.Voting {
+		fail("Voting has not yet started.")
+		return
+	}
+	if context.Event.Closed {
+		fail("Voting is closed.")
+		return
+	}
+
+	teamid, err := strconv.ParseInt(context.FormValue("team"), 10, 64)
+	var team *Team
+	for _, t := range teams {
+		if err == nil && t.ID == TeamID(teamid) {
+			team = t
+		}
+	}
+	if team == nil {
+		fail("Select a team.")
+		return
+	}
+
+	event := context.Event
+	eventChanged := false
+
+	var voter user.UserID
+	if name := context.FormValue("placeholder"); name != "" {
+		judge, err := event.AddPlaceholder(name)
+		if err != nil {
+			fail(err.Error())
+			return
+		}
+		voter = judge.ID
+		if group := context.FormValue("group"); group != "" && group != event.VoterGroup(voter) {
+			if err := event.SetVoterGroup(voter, group); err != nil {
+				fail(err.Error())
+				return
+			}
+		}
+		eventChanged = true
+	} else {
+		id, err := strconv.ParseInt(context.FormValue("voter"), 10, 64)
+		if err != nil {
+			fail("Select a voter or enter a placeholder judge name.")
+			return
+		}
+		voter = user.UserID(id)
+
+		_, isPlaceholder := event.Placeholder(voter)
+		isJammer := false
+		for _, u := range voters {
+			isJammer = isJammer || u.ID == voter
+		}
+		if !isPlaceholder && !isJammer {
+			fail("The voter has not been approved for this event.")
+			return
+		}
+	}
+
+	ballot, err := context.Events.UserBallot(context.Event.ID, voter, team.ID)
+	if err != nil {
+		if err != ErrNotExists {
+			fail(err.Error())
+			return
+		}
+		ballot = nil
+	}
+	if err := event.checkProxyBallot(voter, team, ballot); err != nil {
+		fail(fmt.Sprintf("Cannot enter ballot for %v: %v.", team.Name, err))
+		return
+	}
+	if ballot == nil {
+		ballot = &Ballot{}
+	}
+
+	ballot.Voter = voter
+	ballot.Team = team.ID
+
+	for _, aspect := range AspectDescriptions {
+		score, err := strconv.ParseFloat(context.FormValue(aspect.Name+".Score"), 64)
+		if err != nil {
+			fail(fmt.Sprintf("%v score is missing or invalid.", aspect.Name))
+			return
+		}
+		ballot.setScore(aspect.Name, score)
+		ballot.setComment(aspect.Name, context.FormValue(aspect.Name+".Comment"))
+	}
+
+	ballot.Aspects.EnsureRange()
+	ballot.Aspects.UpdateTotal()
+	ballot.Completed = true
+	ballot.Proxy = true
+	ballot.EnteredBy = context.CurrentUser.ID
+
+	if eventChanged {
+		if err := context.Events.Update(event); err != nil {
+			fail(err.Error())
+			return
+		}
+	}
+
+	if err := context.Events.SubmitBallot(context.Event.ID, ballot); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	context.FlashMessage(fmt.Sprintf("Ballot for %v entered.", team.Name))
+	context.Redirect(context.Event.Path("proxy-ballot"), http.StatusSeeOther)
+}
+
+// setComment sets an aspect comment based on a name.
+func (aspects *Aspects) setComment(name string, comment string) {
+	switch name {
+	case "Theme":
+		aspects.Theme.Comment = comment
+	case "Enjoyment":
+		aspects.Enjoyment.Comment = comment
+	case "Aesthetics":
+		aspects.Aesthetics.Comment = comment
+	case "Innovation":
+		aspects.Innovation.Comment = comment
+	case "Bonus":
+		aspects.Bonus.Comment = comment
+	case "Overall":
+		aspects.Overall.Comment = comment
+	}
+}
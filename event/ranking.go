@@ -0,0 +1,253 @@
+package event
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TieBreaker is a rule for ordering teams with an equal overall score.
+//
+// Group and aspect tie-breakers are written as "group:<name>" and "aspect:<name>".
+type TieBreaker string
+
+const (
+	// TieBreakBallots prefers teams with more completed ballots.
+	TieBreakBallots TieBreaker = "ballots"
+	// TieBreakMedian prefers teams with a higher median overall score.
+	TieBreakMedian TieBreaker = "median"
+
+	tieBreakGroupPrefix  = "group:"
+	tieBreakAspectPrefix = "aspect:"
+)
+
+// MaxTieBreakers is the number of tie-breakers that can be configured.
+const MaxTieBreakers = 4
+
+// scoreEpsilon is the difference below which scores are considered equal.
+const scoreEpsilon = 1e-9
+
+// GroupTieBreaker returns a tie-breaker using a voter group average.
+func GroupTieBreaker(name string) TieBreaker {
+	return TieBreaker(tieBreakGroupPrefix + name)
+}
+
+// AspectTieBreaker returns a tie-breaker using an aspect average.
+func AspectTieBreaker(name string) TieBreaker {
+	return TieBreaker(tieBreakAspectPrefix + name)
+}
+
+// Description returns a human readable description of the tie-breaker.
+func (breaker TieBreaker) Description() string {
+	switch {
+	case breaker == TieBreakBallots:
+		return "Number of ballots"
+	case breaker == TieBreakMedian:
+		return "Median overall score"
+	case strings.HasPrefix(string(breaker), tieBreakGroupPrefix):
+		return strings.TrimPrefix(string(breaker), tieBreakGroupPrefix) + " average"
+	case strings.HasPrefix(string(breaker), tieBreakAspectPrefix):
+		return strings.TrimPrefix(string(breaker), tieBreakAspectPrefix) + " average"
+	}
+	return string(breaker)
+}
+
+// TieBreakerOptions returns all tie-breakers available for the event.
+func (event *Event) TieBreakerOptions() []TieBreaker {
+	options := []TieBreaker{}
+	for _, group := range event.ScoreGroups() {
+		options = append(options, GroupTieBreaker(group.Name))
+	}
+	for _, aspect := range AspectNames {
+		if aspect == "Overall" {
+			continue
+		}
+		options = append(options, AspectTieBreaker(aspect))
+	}
+	return append(options, TieBreakBallots, TieBreakMedian)
+}
+
+// TieBreakersForEdit returns tie-breakers padded with empty values for editing.
+func (event *Event) TieBreakersForEdit() []TieBreaker {
+	breakers := append([]TieBreaker{}, event.TieBreakers...)
+	for len(breakers) < MaxTieBreakers {
+		breakers = append(breakers, "")
+	}
+	return breakers
+}
+
+// IsTieBreakerOption checks whether breaker is available for the event.
+func (event *Event) IsTieBreakerOption(breaker TieBreaker) bool {
+	return slices.Contains(event.TieBreakerOptions(), breaker)
+}
+
+// InvalidTieBreakers returns configured tie-breakers that are no longer available,
+// for example because their voter group was removed.
+func (event *Event) InvalidTieBreakers() []TieBreaker {
+	invalid := []TieBreaker{}
+	for _, breaker := range event.TieBreakers {
+		if !event.IsTieBreakerOption(breaker) {
+			invalid = append(invalid, breaker)
+		}
+	}
+	return invalid
+}
+
+// verifyTieBreakers checks that tie-breakers are known and not repeated.
+func (event *Event) verifyTieBreakers(breakers []TieBreaker) error {
+	for i, breaker := range breakers {
+		if !event.IsTieBreakerOption(breaker) {
+			if name, ok := strings.CutPrefix(string(breaker), tieBreakGroupPrefix); ok {
+				return fmt.Errorf("tie-breaker uses voter group %q, which does not exist", name)
+			}
+			return fmt.Errorf("unknown tie-breaker %q", breaker)
+		}
+		for _, other := range breakers[:i] {
+			if other == breaker {
+				return fmt.Errorf("tie-breaker %q is used twice", breaker.Description())
+			}
+		}
+	}
+	return nil
+}
+
+// renameGroupTieBreakers updates group tie-breakers to follow renamed groups.
+//
+// Groups are matched by their previous names, same as in SetGroups.
+func renameGroupTieBreakers(breakers []TieBreaker, previous []string, groups []VoterGroup) []TieBreaker {
+	renamed := append([]TieBreaker{}, breakers...)
+	for i, breaker := range renamed {
+		for k, name := range previous {
+			if name != "" && k < len(groups) && breaker == GroupTieBreaker(name) {
+				renamed[i] = GroupTieBreaker(groups[k].Name)
+				break
+			}
+		}
+	}
+	return renamed
+}
+
+// value returns the value of the tie-breaker for result, higher is better.
+func (breaker TieBreaker) value(result *TeamResult) float64 {
+	switch {
+	case breaker == TieBreakBallots:
+		return float64(result.Complete)
+	case breaker == TieBreakMedian:
+		return result.MedianOverall()
+	case strings.HasPrefix(string(breaker), tieBreakGroupPrefix):
+		name := strings.TrimPrefix(string(breaker), tieBreakGroupPrefix)
+		return result.GroupAverage(name).Average.Overall.Score
+	case strings.HasPrefix(string(breaker), tieBreakAspectPrefix):
+		name := strings.TrimPrefix(string(breaker), tieBreakAspectPrefix)
+		return result.Average.Score(name)
+	}
+	return 0
+}
+
+// MedianOverall returns the median overall score of completed ballots.
+func (info *TeamResult) MedianOverall() float64 {
+	scores := []float64{}
+	for _, ballot := range info.Ballots {
+		if ballot.Completed {
+			scores = append(scores, ballot.Overall.Score)
+		}
+	}
+	if len(scores) == 0 {
+		return 0
+	}
+	sort.Float64s(scores)
+	middle := len(scores) / 2
+	if len(scores)%2 == 0 {
+		return (scores[middle-1] + scores[middle]) / 2
+	}
+	return scores[middle]
+}
+
+// compareScores compares a and b, returning -1 when a is better.
+func compareScores(a, b float64) int {
+	switch {
+	case math.Abs(a-b) < scoreEpsilon:
+		return 0
+	case a > b:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareResults compares results by overall score and then by tie-breakers.
+func compareResults(a, b *TeamResult, breakers []TieBreaker) int {
+	if cmp := compareScores(a.Average.Overall.Score, b.Average.Overall.Score); cmp != 0 {
+		return cmp
+	}
+	for _, breaker := range breakers {
+		if cmp := compareScores(breaker.value(a), breaker.value(b)); cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// RankResults sorts results and assigns placements to competing teams.
+//
+// Noncompeting and unscored teams are placed last without a placement. Teams that remain
+// tied after all tie-breakers share the placement and are ordered by name.
+func RankResults(results []*TeamResult, event *Event) {
+	places, shared := rankPlaces(results,
+		func(result *TeamResult) bool { return result.IsRanked(event.Submission) },
+		func(a, b *TeamResult) int {
+			if a.IsCompeting(event.Submission) != b.IsCompeting(event.Submission) {
+				if a.IsCompeting(event.Submission) {
+					return -1
+				}
+				return 1
+			}
+			return compareResults(a, b, event.TieBreakers)
+		},
+		func(a, b *TeamResult) bool {
+			if a.Team.Name != b.Team.Name {
+				return a.Team.Less(b.Team)
+			}
+			return a.Team.ID < b.Team.ID
+		},
+	)
+	for i, result := range results {
+		result.Place = places[i]
+		result.SharedPlace = shared[i]
+	}
+}
+
+// rankPlaces sorts items with ranked items first and returns their placements,
+// which are zero for items that are not ranked.
+//
+// Items are ordered by compare and then by less. Ranked items share the
+// placement when compare returns zero.
+func rankPlaces[T any](items []T, ranked func(T) bool, compare func(a, b T) int, less func(a, b T) bool) (places []int, shared []bool) {
+	sort.SliceStable(items, func(i, k int) bool {
+		a, b := items[i], items[k]
+		if ranked(a) != ranked(b) {
+			return ranked(a)
+		}
+		if cmp := compare(a, b); cmp != 0 {
+			return cmp < 0
+		}
+		return less(a, b)
+	})
+
+	places = make([]int, len(items))
+	shared = make([]bool, len(items))
+	for i, item := range items {
+		if !ranked(item) {
+			continue
+		}
+		places[i] = i + 1
+		if i > 0 && ranked(items[i-1]) && compare(items[i-1], item) == 0 {
+			places[i] = places[i-1]
+			shared[i] = true
+			shared[i-1] = true
+		}
+	}
+	return places, shared
+}
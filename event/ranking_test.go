@@ -0,0 +1,240 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+type resultOption func(result *TeamResult)
+
+// rankedResult creates a submitted competing team result with the overall score.
+func rankedResult(name string, overall float64, options ...resultOption) *TeamResult {
+	result := &TeamResult{
+		Team: &Team{
+			Name: name,
+			Game: Game{
+				Name:  name + " Game",
+				Links: []Link{{Type: LinkItch, URL: "https://example.itch.io/game"}},
+			},
+		},
+		Average: Aspects{Overall: Aspect{Score: overall}},
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+func withBallots(scores ...float64) resultOption {
+	return func(result *TeamResult) {
+		for _, score := range scores {
+			result.Ballots = append(result.Ballots, &Ballot{Completed: true, Aspects: Aspects{Overall: Aspect{Score: score}}})
+		}
+		result.Complete = len(scores)
+	}
+}
+
+func withGroup(name string, weight, overall float64) resultOption {
+	return func(result *TeamResult) {
+		result.GroupAverages = append(result.GroupAverages, GroupAverage{
+			Name:    name,
+			Weight:  weight,
+			Count:   1,
+			Average: Aspects{Overall: Aspect{Score: overall}},
+		})
+	}
+}
+
+func withEnjoyment(score float64) resultOption {
+	return func(result *TeamResult) { result.Average.Enjoyment.Score = score }
+}
+
+func asNoncompeting(result *TeamResult) { result.Game.Noncompeting = true }
+
+func asUnsubmitted(result *TeamResult) { result.Game.Links = nil }
+
+func TestRankResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		breakers []TieBreaker
+		results  []*TeamResult
+		expect   []string
+	}{
+		{
+			name: "overall score",
+			results: []*TeamResult{
+				rankedResult("A", 3),
+				rankedResult("B", 4),
+				rankedResult("C", 3.5),
+			},
+			expect: []string{"B#1", "C#2", "A#3"},
+		},
+		{
+			name: "shared place ordered by name",
+			results: []*TeamResult{
+				rankedResult("Team 10", 4),
+				rankedResult("Team 3", 3),
+				rankedResult("Team 2", 4),
+			},
+			expect: []string{"Team 2#1=", "Team 10#1=", "Team 3#3"},
+		},
+		{
+			name:     "ballot count",
+			breakers: []TieBreaker{TieBreakBallots},
+			results: []*TeamResult{
+				rankedResult("A", 4, withBallots(4, 4)),
+				rankedResult("B", 4, withBallots(4, 4, 4)),
+			},
+			expect: []string{"B#1", "A#2"},
+		},
+		{
+			name:     "median",
+			breakers: []TieBreaker{TieBreakMedian},
+			results: []*TeamResult{
+				rankedResult("A", 4, withBallots(3, 4, 5)),
+				rankedResult("B", 4, withBallots(2, 5, 5)),
+				rankedResult("C", 4, withBallots(3, 4, 5, 4)),
+			},
+			expect: []string{"B#1", "A#2=", "C#2="},
+		},
+		{
+			name:     "group average",
+			breakers: []TieBreaker{GroupTieBreaker("Judges")},
+			results: []*TeamResult{
+				rankedResult("A", 4, withGroup("Judges", 30, 3)),
+				rankedResult("B", 4, withGroup("Judges", 30, 4.5)),
+			},
+			expect: []string{"B#1", "A#2"},
+		},
+		{
+			name:     "aspect average",
+			breakers: []TieBreaker{AspectTieBreaker("Enjoyment")},
+			results: []*TeamResult{
+				rankedResult("A", 4, withEnjoyment(3)),
+				rankedResult("B", 4, withEnjoyment(4.5)),
+			},
+			expect: []string{"B#1", "A#2"},
+		},
+		{
+			name:     "tie-breakers in order",
+			breakers: []TieBreaker{TieBreakBallots, TieBreakMedian},
+			results: []*TeamResult{
+				rankedResult("A", 4, withBallots(3, 5)),
+				rankedResult("B", 4, withBallots(4, 5)),
+				rankedResult("C", 4, withBallots(1, 1, 1)),
+			},
+			expect: []string{"C#1", "B#2", "A#3"},
+		},
+		{
+			name:     "score before tie-breakers",
+			breakers: []TieBreaker{TieBreakBallots},
+			results: []*TeamResult{
+				rankedResult("A", 3, withBallots(3, 3, 3)),
+				rankedResult("B", 4, withBallots(4)),
+			},
+			expect: []string{"B#1", "A#2"},
+		},
+		{
+			name: "noncompeting and unsubmitted last",
+			results: []*TeamResult{
+				rankedResult("Noncompeting", 5, asNoncompeting),
+				rankedResult("Unsubmitted", 4.5, asUnsubmitted),
+				rankedResult("A", 3),
+				rankedResult("B", 2),
+			},
+			expect: []string{"A#1", "B#2", "Noncompeting-", "Unsubmitted-"},
+		},
+		{
+			name: "unscored after competing",
+			results: []*TeamResult{
+				rankedResult("Noncompeting", 5, asNoncompeting),
+				rankedResult("Unscored", 0, withGroup("Guests", 0, 5)),
+				rankedResult("A", 1, withGroup(DefaultGroupName, 100, 1)),
+			},
+			expect: []string{"A#1", "Unscored-", "Noncompeting-"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := &Event{TieBreakers: test.breakers}
+			RankResults(test.results, event)
+
+			got := []string{}
+			for _, result := range test.results {
+				switch {
+				case result.Place == 0:
+					got = append(got, result.Team.Name+"-")
+				case result.SharedPlace:
+					got = append(got, fmt.Sprintf("%v#%v=", result.Team.Name, result.Place))
+				default:
+					got = append(got, fmt.Sprintf("%v#%v", result.Team.Name, result.Place))
+				}
+			}
+			if !slices.Equal(got, test.expect) {
+				t.Errorf("got %v, expected %v", got, test.expect)
+			}
+		})
+	}
+}
+
+func TestTieBreakerValue(t *testing.T) {
+	result := rankedResult("A", 4, withBallots(2, 3, 5, 4), withGroup("Judges", 30, 3.5), withEnjoyment(4.25))
+	tests := []struct {
+		breaker TieBreaker
+		value   float64
+	}{
+		{TieBreakBallots, 4},
+		{TieBreakMedian, 3.5},
+		{GroupTieBreaker("Judges"), 3.5},
+		{GroupTieBreaker("Missing"), 0},
+		{AspectTieBreaker("Enjoyment"), 4.25},
+		{TieBreaker("unknown"), 0},
+	}
+	for _, test := range tests {
+		if value := test.breaker.value(result); value != test.value {
+			t.Errorf("%v: got %v, expected %v", test.breaker, value, test.value)
+		}
+	}
+}
+
+func TestVerifyTieBreakers(t *testing.T) {
+	event := &Event{Groups: []VoterGroup{{Name: "Judges", Weight: 30}}}
+
+	valid := []TieBreaker{GroupTieBreaker("Judges"), GroupTieBreaker(DefaultGroupName), AspectTieBreaker("Theme"), TieBreakBallots, TieBreakMedian}
+	if err := event.verifyTieBreakers(valid); err != nil {
+		t.Error(err)
+	}
+
+	invalid := [][]TieBreaker{
+		{GroupTieBreaker("Industry")},
+		{AspectTieBreaker("Overall")},
+		{TieBreaker("unknown")},
+		{TieBreakBallots, TieBreakBallots},
+	}
+	for _, breakers := range invalid {
+		if err := event.verifyTieBreakers(breakers); err == nil {
+			t.Errorf("%v: expected error", breakers)
+		}
+	}
+
+	event.TieBreakers = []TieBreaker{GroupTieBreaker("Judges"), TieBreakBallots}
+	if invalid := event.InvalidTieBreakers(); len(invalid) != 0 {
+		t.Errorf("unexpected invalid tie-breakers %v", invalid)
+	}
+
+	previous := []string{"Judges", ""}
+	groups := []VoterGroup{{Name: "Jury", Weight: 30}, {Name: "Guests"}}
+	renamed := renameGroupTieBreakers(event.TieBreakers, previous, groups)
+	if !slices.Equal(renamed, []TieBreaker{GroupTieBreaker("Jury"), TieBreakBallots}) {
+		t.Errorf("renamed: got %v", renamed)
+	}
+
+	if err := event.SetGroups(previous, groups); err != nil {
+		t.Fatal(err)
+	}
+	if invalid := event.InvalidTieBreakers(); !slices.Equal(invalid, []TieBreaker{GroupTieBreaker("Judges")}) {
+		t.Errorf("invalid: got %v", invalid)
+	}
+}
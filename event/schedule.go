@@ -0,0 +1,298 @@
+package event
+
+import (
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/adinfinit/jamvote/site"
+)
+
+// DefaultSlotMinutes is the default length of a presentation.
+const DefaultSlotMinutes = 5
+
+// ScheduleRules control how the presentation order is generated.
+type ScheduleRules struct {
+	// Shuffle randomizes the order, otherwise teams are ordered by name.
+	Shuffle bool
+	// AlternateNoncompeting spreads noncompeting teams between competing ones.
+	AlternateNoncompeting bool
+	// VideoLast puts games with video builds at the end.
+	VideoLast bool
+}
+
+// Schedule is the presentation order of team demos.
+type Schedule struct {
+	Start time.Time
+	// SlotMinutes is the length of a single presentation.
+	SlotMinutes int
+	// GapMinutes is the changeover time between presentations.
+	GapMinutes int
+	Rules      ScheduleRules
+	// Order contains teams in presentation order.
+	Order []TeamID
+}
+
+// ScheduleSlot is a single presentation in the schedule.
+type ScheduleSlot struct {
+	Number int
+	Team   *Team
+	Start  time.Time
+	End    time.Time
+}
+
+// HasTime returns whether the slot has been assigned a time.
+func (slot *ScheduleSlot) HasTime() bool {
+	return !slot.Start.IsZero()
+}
+
+// SlotLength returns the presentation length, using the default when unset.
+func (schedule *Schedule) SlotLength() int {
+	if schedule.SlotMinutes <= 0 {
+		return DefaultSlotMinutes
+	}
+	return schedule.SlotMinutes
+}
+
+// GenerateOrder orders submitted teams according to rules.
+func GenerateOrder(teams []*Team, submission SubmissionRules, rules ScheduleRules, rng *rand.Rand) []TeamID {
+	submitted := []*Team{}
+	for _, team := range teams {
+		if team.HasSubmitted(submission) {
+			submitted = append(submitted, team)
+		}
+	}
+	sort.Slice(submitted, func(i, k int) bool {
+		return submitted[i].Less(submitted[k])
+	})
+	if rules.Shuffle {
+		rng.Shuffle(len(submitted), func(i, k int) {
+			submitted[i], submitted[k] = submitted[k], submitted[i]
+		})
+	}
+
+	main, video := []*Team{}, []*Team{}
+	for _, team := range submitted {
+		if rules.VideoLast && team.Game.HasVideo() {
+			video = append(video, team)
+		} else {
+			main = append(main, team)
+		}
+	}
+
+	ordered := []TeamID{}
+	for _, part := range [][]*Team{main, video} {
+		if rules.AlternateNoncompeting {
+			part = alternateNoncompeting(part)
+		}
+		for _, team := range part {
+			ordered = append(ordered, team.ID)
+		}
+	}
+	return ordered
+}
+
+// alternateNoncompeting spreads noncompeting teams evenly between competing teams.
+//
+// All teams are expected to have submitted.
+func alternateNoncompeting(teams []*Team) []*Team {
+	competing, noncompeting := []*Team{}, []*Team{}
+	for _, team := range teams {
+		if !team.Game.Noncompeting {
+			competing = append(competing, team)
+		} else {
+			noncompeting = append(noncompeting, team)
+		}
+	}
+
+	total := len(teams)
+	result := make([]*Team, 0, total)
+	c, n := 0, 0
+	for i := 0; i < total; i++ {
+		// take a noncompeting team once it falls behind its share of the order
+		if n < len(noncompeting) && (c >= len(competing) || (n+1)*total <= (i+1)*len(noncompeting)) {
+			result = append(result, noncompeting[n])
+			n++
+		} else {
+			result = append(result, competing[c])
+			c++
+		}
+	}
+	return result
+}
+
+// Slots assigns times to submitted teams in schedule order.
+//
+// Submitted teams missing from the order are appended by name.
+func (schedule *Schedule) Slots(teams []*Team, submission SubmissionRules) []ScheduleSlot {
+	byID := map[TeamID]*Team{}
+	submitted := []*Team{}
+	for _, team := range teams {
+		if team.HasSubmitted(submission) {
+			byID[team.ID] = team
+			submitted = append(submitted, team)
+		}
+	}
+
+	ordered := []*Team{}
+	for _, id := range schedule.Order {
+		if team, ok := byID[id]; ok {
+			ordered = append(ordered, team)
+			delete(byID, id)
+		}
+	}
+	remaining := []*Team{}
+	for _, team := range submitted {
+		if _, ok := byID[team.ID]; ok {
+			remaining = append(remaining, team)
+		}
+	}
+	sort.Slice(remaining, func(i, k int) bool {
+		return remaining[i].Less(remaining[k])
+	})
+	ordered = append(ordered, remaining...)
+
+	slot := time.Duration(schedule.SlotLength()) * time.Minute
+	step := slot + time.Duration(schedule.GapMinutes)*time.Minute
+
+	slots := []ScheduleSlot{}
+	for i, team := range ordered {
+		entry := ScheduleSlot{Number: i + 1, Team: team}
+		if !schedule.Start.IsZero() {
+			entry.Start = schedule.Start.Add(time.Duration(i) * step).In(site.APTLocation)
+			entry.End = entry.Start.Add(slot)
+		}
+		slots = append(slots, entry)
+	}
+	return slots
+}
+
+// LiveSlots finds the slot presenting at now and the one after it.
+func LiveSlots(slots []ScheduleSlot, now time.Time) (current, next *ScheduleSlot) {
+	for i := range slots {
+		slot := &slots[i]
+		if !slot.HasTime() {
+			return nil, nil
+		}
+		if !now.Before(slot.Start) && now.Before(slot.End) {
+			current = slot
+			continue
+		}
+		if slot.Start.After(now) {
+			return current, slot
+		}
+	}
+	return current, nil
+}
+
+// Schedule handles editing the presentation schedule.
+func (server *Server) Schedule(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to edit the schedule.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	schedule := &context.Event.Schedule
+	render := func() {
+		context.Data["Schedule"] = schedule
+		context.Data["Slots"] = schedule.Slots(teams, context.Event.Submission)
+		context.Render("event-schedule")
+	}
+	fail := func(message string) {
+		context.FlashErrorNow(message)
+		context.Response.WriteHeader(http.StatusBadRequest)
+		render()
+	}
+
+	if context.Request.Method != http.MethodPost {
+		render()
+		return
+	}
+
+	switch context.FormValue("action") {
+	case "generate":
+		schedule.Rules = ScheduleRules{
+			Shuffle:               context.FormValue("Shuffle") == "true",
+			AlternateNoncompeting: context.FormValue("AlternateNoncompeting") == "true",
+			VideoLast:             context.FormValue("VideoLast") == "true",
+		}
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		schedule.Order = GenerateOrder(teams, context.Event.Submission, schedule.Rules, rng)
+	case "save":
+		start := context.FormValue("Start")
+		if start == "" {
+			schedule.Start = time.Time{}
+		} else {
+			t, err := time.ParseInLocation("2006-01-02T15:04", start, site.APTLocation)
+			if err != nil {
+				fail(err.Error())
+				return
+			}
+			schedule.Start = t
+		}
+
+		slotMinutes, err := strconv.Atoi(context.FormValue("SlotMinutes"))
+		if err != nil || slotMinutes < 1 {
+			fail("Presentation length must be at least 1 minute.")
+			return
+		}
+		gapMinutes, err := strconv.Atoi(context.FormValue("GapMinutes"))
+		if err != nil || gapMinutes < 0 {
+			fail("Changeover time cannot be negative.")
+			return
+		}
+		schedule.SlotMinutes = slotMinutes
+		schedule.GapMinutes = gapMinutes
+
+		known := map[TeamID]bool{}
+		for _, team := range teams {
+			known[team.ID] = true
+		}
+		order := []TeamID{}
+		for _, value := range context.Request.Form["order"] {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || !known[TeamID(id)] {
+				fail("Unknown team in the order.")
+				return
+			}
+			order = append(order, TeamID(id))
+		}
+		schedule.Order = order
+	default:
+		fail("Unknown schedule action.")
+		return
+	}
+
+	if err := context.Events.Update(context.Event); err != nil {
+		context.FlashErrorNow(err.Error())
+		context.Response.WriteHeader(http.StatusInternalServerError)
+		render()
+		return
+	}
+
+	context.Redirect(context.Event.Path("schedule"), http.StatusSeeOther)
+}
+
+// ScheduleLive displays the team presenting now and the one up next.
+func (server *Server) ScheduleLive(context *Context) {
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	slots := context.Event.Schedule.Slots(teams, context.Event.Submission)
+	current, next := LiveSlots(slots, time.Now())
+
+	context.Data["Slots"] = slots
+	context.Data["Current"] = current
+	context.Data["Next"] = next
+	context.Render("event-schedule-live")
+}
@@ -0,0 +1,109 @@
+package event
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGenerateOrder(t *testing.T) {
+	teams := []*Team{}
+	add := func(name string, noncompeting, video bool) {
+		team := &Team{ID: TeamID(len(teams) + 1), Name: name}
+		team.Game.Name = name
+		team.Game.Links = []Link{{Type: LinkDownload, URL: "https://example.com/" + name}}
+		team.Game.Noncompeting = noncompeting
+		if video {
+			team.Game.Platforms = []Platform{PlatformVideo}
+		}
+		teams = append(teams, team)
+	}
+	add("A", false, false)
+	add("B", false, false)
+	add("C", true, false)
+	add("D", false, true)
+	add("E", false, false)
+	add("F", true, false)
+	add("G", false, false)
+	unsubmitted := &Team{ID: 100, Name: "Unsubmitted"}
+	teams = append(teams, unsubmitted)
+
+	names := func(order []TeamID) string {
+		result := ""
+		for _, id := range order {
+			result += teams[id-1].Name
+		}
+		return result
+	}
+
+	tests := []struct {
+		rules ScheduleRules
+		want  string
+	}{
+		{ScheduleRules{}, "ABCDEFG"},
+		{ScheduleRules{VideoLast: true}, "ABCEFGD"},
+		{ScheduleRules{AlternateNoncompeting: true}, "ABDCEGF"},
+		{ScheduleRules{AlternateNoncompeting: true, VideoLast: true}, "ABCEGFD"},
+	}
+	for _, test := range tests {
+		got := names(GenerateOrder(teams, SubmissionRules{}, test.rules, rand.New(rand.NewSource(1))))
+		if got != test.want {
+			t.Errorf("%+v: got %v, want %v", test.rules, got, test.want)
+		}
+	}
+
+	shuffled := GenerateOrder(teams, SubmissionRules{}, ScheduleRules{Shuffle: true, VideoLast: true}, rand.New(rand.NewSource(1)))
+	if len(shuffled) != 7 || shuffled[6] != 4 || slices.Contains(shuffled, unsubmitted.ID) {
+		t.Errorf("unexpected shuffled order %v", shuffled)
+	}
+}
+
+func TestSlots(t *testing.T) {
+	teams := []*Team{}
+	for i := 1; i <= 3; i++ {
+		team := &Team{ID: TeamID(i), Name: fmt.Sprint("Team ", i)}
+		team.Game.Name = team.Name
+		team.Game.Links = []Link{{Type: LinkDownload, URL: "https://example.com/"}}
+		teams = append(teams, team)
+	}
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	schedule := &Schedule{Start: start, SlotMinutes: 5, GapMinutes: 1, Order: []TeamID{3, 1, 99}}
+	slots := schedule.Slots(teams, SubmissionRules{})
+
+	order := []TeamID{}
+	for _, slot := range slots {
+		order = append(order, slot.Team.ID)
+	}
+	if !slices.Equal(order, []TeamID{3, 1, 2}) {
+		t.Fatalf("got order %v", order)
+	}
+	if !slots[2].Start.Equal(start.Add(12*time.Minute)) || !slots[2].End.Equal(start.Add(17*time.Minute)) {
+		t.Errorf("unexpected last slot %v – %v", slots[2].Start, slots[2].End)
+	}
+
+	tests := []struct {
+		at            time.Duration
+		current, next TeamID
+	}{
+		{-time.Minute, 0, 3},
+		{2 * time.Minute, 3, 1},
+		{5*time.Minute + 30*time.Second, 0, 1},
+		{13 * time.Minute, 2, 0},
+		{time.Hour, 0, 0},
+	}
+	for _, test := range tests {
+		current, next := LiveSlots(slots, start.Add(test.at))
+		id := func(slot *ScheduleSlot) TeamID {
+			if slot == nil {
+				return 0
+			}
+			return slot.Team.ID
+		}
+		if id(current) != test.current || id(next) != test.next {
+			t.Errorf("at %v: got %v, %v; want %v, %v", test.at, id(current), id(next), test.current, test.next)
+		}
+	}
+}
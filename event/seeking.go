@@ -0,0 +1,263 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// SeekerRepo is used to manage the looking-for-team board of an event.
+type SeekerRepo interface {
+	Seekers(eventid EventID) ([]*Seeker, error)
+	UpdateSeeker(eventid EventID, seeker *Seeker) error
+	DeleteSeeker(eventid EventID, userid user.UserID) error
+}
+
+const (
+	// MaxSeekerTags is the maximum number of skills or tools on a card.
+	MaxSeekerTags = 12
+	// MaxSeekerText is the maximum length of the free text on a card.
+	MaxSeekerText = 1000
+)
+
+// Seeker is a profile card of a user looking for a team.
+type Seeker struct {
+	User user.UserID
+
+	Skills []string `datastore:",noindex"`
+	Tools  []string `datastore:",noindex"`
+	// Interests describes what the user would like to make.
+	Interests string `datastore:",noindex"`
+
+	Updated time.Time `datastore:",noindex"`
+}
+
+// Verify verifies whether the card has valid state.
+func (seeker *Seeker) Verify() error {
+	if len(seeker.Skills) == 0 && len(seeker.Tools) == 0 && seeker.Interests == "" {
+		return errors.New("card must list skills, tools or what you want to make")
+	}
+	if len(seeker.Skills) > MaxSeekerTags || len(seeker.Tools) > MaxSeekerTags {
+		return fmt.Errorf("card can list at most %v skills and %v tools", MaxSeekerTags, MaxSeekerTags)
+	}
+	if len(seeker.Interests) > MaxSeekerText {
+		return fmt.Errorf("description can be at most %v characters", MaxSeekerText)
+	}
+	return nil
+}
+
+// SeekerCard is a card on the board together with the user and their teams.
+type SeekerCard struct {
+	*Seeker
+	User  *user.User
+	Teams []*Team
+}
+
+// Unteamed is an approved jammer who is not a member of any team.
+type Unteamed struct {
+	User   *user.User
+	Seeker *Seeker
+}
+
+// LookingForTeamOpen returns whether users can post to and invite from the board.
+//
+// The board closes together with registration.
+func (event *Event) LookingForTeamOpen() bool {
+	return !event.Closed && event.Registration
+}
+
+// parseTagList parses a comma or newline separated list, ignoring duplicates.
+func parseTagList(text string) []string {
+	tags := []string{}
+	for _, tag := range parseWordList(text) {
+		if !slices.ContainsFunc(tags, func(existing string) bool {
+			return strings.EqualFold(existing, tag)
+		}) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// teamsOf returns teams where userid is a member.
+func teamsOf(teams []*Team, userid user.UserID) []*Team {
+	result := []*Team{}
+	for _, team := range teams {
+		if team.HasMemberID(userid) {
+			result = append(result, team)
+		}
+	}
+	return result
+}
+
+// LookingForTeam displays the looking-for-team board and handles posting cards and invitations.
+func (server *Server) LookingForTeam(context *Context) {
+	if context.CurrentUser == nil {
+		context.FlashError("You must be logged in to use the looking-for-team board.")
+		context.Redirect("/user/login", http.StatusSeeOther)
+		return
+	}
+
+	isAdmin := context.CurrentUser.IsAdmin()
+	open := context.Event.LookingForTeamOpen()
+	context.Data["BoardOpen"] = open
+	if !open && !isAdmin {
+		context.Render("event-looking-for-team")
+		return
+	}
+
+	if context.Request.Method == http.MethodPost {
+		server.updateLookingForTeam(context)
+		context.Redirect(context.Event.Path("looking-for-team"), http.StatusSeeOther)
+		return
+	}
+
+	seekers, err := context.Events.Seekers(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get cards: %v", err))
+	}
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get teams: %v", err))
+	}
+	sort.Slice(teams, func(i, k int) bool {
+		return teams[i].Less(teams[k])
+	})
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get list of users: %v", err))
+	}
+
+	usersByID := map[user.UserID]*user.User{}
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	cards := []SeekerCard{}
+	for _, seeker := range seekers {
+		u, ok := usersByID[seeker.User]
+		if !ok {
+			continue
+		}
+		if seeker.User == context.CurrentUser.ID {
+			context.Data["OwnSeeker"] = seeker
+		}
+		cards = append(cards, SeekerCard{
+			Seeker: seeker,
+			User:   u,
+			Teams:  teamsOf(teams, seeker.User),
+		})
+	}
+	sort.SliceStable(cards, func(i, k int) bool {
+		a, b := cards[i], cards[k]
+		if (len(a.Teams) == 0) != (len(b.Teams) == 0) {
+			return len(a.Teams) == 0
+		}
+		return a.Updated.After(b.Updated)
+	})
+
+	context.Data["Cards"] = cards
+	context.Data["InviteTeams"] = teamsOf(teams, context.CurrentUser.ID)
+
+	if isAdmin {
+		unteamed := []Unteamed{}
+		for _, userid := range context.Event.Jammers {
+			u, ok := usersByID[userid]
+			if !ok || len(teamsOf(teams, userid)) > 0 {
+				continue
+			}
+			entry := Unteamed{User: u}
+			if index := slices.IndexFunc(seekers, func(seeker *Seeker) bool {
+				return seeker.User == userid
+			}); index >= 0 {
+				entry.Seeker = seekers[index]
+			}
+			unteamed = append(unteamed, entry)
+		}
+		sort.Slice(unteamed, func(i, k int) bool {
+			return unteamed[i].User.Name < unteamed[k].User.Name
+		})
+		context.Data["Unteamed"] = unteamed
+	}
+
+	context.Render("event-looking-for-team")
+}
+
+// updateLookingForTeam posts or removes the card of the current user or sends an invitation.
+func (server *Server) updateLookingForTeam(context *Context) {
+	current := context.CurrentUser
+
+	switch context.FormValue("action") {
+	case "save":
+		seeker := &Seeker{
+			User:      current.ID,
+			Skills:    parseTagList(context.FormValue("Skills")),
+			Tools:     parseTagList(context.FormValue("Tools")),
+			Interests: context.FormValue("Interests"),
+			Updated:   time.Now().UTC(),
+		}
+		if err := seeker.Verify(); err != nil {
+			context.FlashError(err.Error())
+			return
+		}
+		if err := context.Events.UpdateSeeker(context.Event.ID, seeker); err != nil {
+			context.FlashError(fmt.Sprintf("Unable to save card: %v", err))
+			return
+		}
+		context.FlashMessage("Your card is on the board.")
+	case "remove":
+		userid := current.ID
+		if current.IsAdmin() && context.FormValue("user") != "" {
+			id, err := strconv.ParseInt(context.FormValue("user"), 10, 64)
+			if err != nil {
+				context.FlashError("Invalid user.")
+				return
+			}
+			userid = user.UserID(id)
+		}
+		if err := context.Events.DeleteSeeker(context.Event.ID, userid); err != nil {
+			context.FlashError(fmt.Sprintf("Unable to remove card: %v", err))
+			return
+		}
+		context.FlashMessage("Card removed from the board.")
+	case "invite":
+		teamid, errTeam := strconv.ParseInt(context.FormValue("team"), 10, 64)
+		userid, errUser := strconv.ParseInt(context.FormValue("user"), 10, 64)
+		if errTeam != nil || errUser != nil {
+			context.FlashError("Invalid team or user.")
+			return
+		}
+		team, err := context.Events.TeamByID(context.Event.ID, TeamID(teamid))
+		if err != nil {
+			context.FlashError(fmt.Sprintf("Unable to get team: %v", err))
+			return
+		}
+		if !team.HasMember(current) {
+			context.FlashError("You can only invite to your own teams.")
+			return
+		}
+		invitee, err := context.Users.ByID(user.UserID(userid))
+		if err != nil {
+			context.FlashError(fmt.Sprintf("Unable to get user: %v", err))
+			return
+		}
+		if _, err := team.AddInvite(invitee, current.ID); err != nil {
+			context.FlashError(err.Error())
+			return
+		}
+		if err := context.Events.UpdateTeam(context.Event.ID, team); err != nil {
+			context.FlashError(fmt.Sprintf("Unable to update team: %v", err))
+			return
+		}
+		context.FlashMessage(fmt.Sprintf("Invited %v to %v.", invitee.Name, team.Name))
+	default:
+		context.FlashError("Unknown board action.")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path"
 	"sort"
 
+	"github.com/adinfinit/jamvote/blob"
 	"github.com/adinfinit/jamvote/site"
 	"github.com/adinfinit/jamvote/user"
 )
@@ -14,6 +15,13 @@ import (
 type Server struct {
 	Site *site.Server
 	DB   DB
+	// Blobs stores uploaded images, uploads are disabled when nil.
+	Blobs blob.Store
+	// LinkChecker checks game links, NewLinkChecker is used when nil.
+	LinkChecker *LinkChecker
+	// PlayDomain is the domain for serving hosted builds, e.g. "https://play.example.com".
+	// When set, builds are not served from other domains.
+	PlayDomain string
 
 	Users *user.Server
 }
@@ -22,26 +30,61 @@ type Server struct {
 func (server *Server) Register(router *http.ServeMux) {
 	router.HandleFunc("/", server.HandlerMaybe(server.List))
 	router.HandleFunc("/event/create", server.HandlerMaybe(server.CreateEvent))
+	router.HandleFunc("/cron/check-links", server.HandlerMaybe(server.CronCheckLinks))
 
 	router.HandleFunc("/event/{eventid}", server.Handler(server.Dashboard))
 	router.HandleFunc("/event/{eventid}/edit", server.Handler(server.EditEvent))
 	router.HandleFunc("/event/{eventid}/jammers", server.Handler(server.Jammers))
 	router.HandleFunc("/event/{eventid}/linking", server.Handler(server.Linking))
 	router.HandleFunc("/event/{eventid}/linking-approve-all", server.Handler(server.LinkingApproveAll))
+	router.HandleFunc("/event/{eventid}/linking/accept", server.Handler(server.LinkingAccept))
+	router.HandleFunc("/event/{eventid}/linking/accept-all", server.Handler(server.LinkingAcceptAll))
+	router.HandleFunc("/event/{eventid}/claims", server.Handler(server.Claims))
+	router.HandleFunc("/event/{eventid}/notifications", server.Handler(server.Notifications))
+	router.HandleFunc("/event/{eventid}/links", server.Handler(server.LinkHealth))
 	router.HandleFunc("/event/{eventid}/teams", server.Handler(server.Teams))
+	router.HandleFunc("/event/{eventid}/migrate-links", server.Handler(server.MigrateLinks))
+	router.HandleFunc("/event/{eventid}/looking-for-team", server.Handler(server.LookingForTeam))
 	router.HandleFunc("/event/{eventid}/voting", server.Handler(server.Voting))
 	router.HandleFunc("/event/{eventid}/fill-queue", server.Handler(server.FillQueue))
 	router.HandleFunc("/event/{eventid}/progress", server.Handler(server.Progress))
 	router.HandleFunc("/event/{eventid}/reveal", server.Handler(server.Reveal))
 	router.HandleFunc("/event/{eventid}/results", server.Handler(server.Results))
+	router.HandleFunc("/event/{eventid}/results/{aspect}", server.Handler(server.AspectResults))
+	router.HandleFunc("/event/{eventid}/results/{aspect}/csv", server.Handler(server.AspectResultsCSV))
+	router.HandleFunc("/event/{eventid}/results/{aspect}/json", server.Handler(server.AspectResultsJSON))
 
 	router.HandleFunc("/event/{eventid}/ballots.csv", server.Handler(server.BallotsCSV))
+	router.HandleFunc("/event/{eventid}/moderation", server.Handler(server.Moderation))
+	router.HandleFunc("/event/{eventid}/anomalies", server.Handler(server.Anomalies))
+	router.HandleFunc("/event/{eventid}/anomalies/{userid}", server.Handler(server.Anomalies))
+	router.HandleFunc("/event/{eventid}/simulation", server.Handler(server.Simulation))
+	router.HandleFunc("/event/{eventid}/proxy-ballot", server.Handler(server.ProxyBallot))
+	router.HandleFunc("/event/{eventid}/ballot-sheets", server.Handler(server.BallotSheets))
+	router.HandleFunc("/event/{eventid}/table-cards", server.Handler(server.TableCards))
+	router.HandleFunc("/event/{eventid}/kiosk", server.Handler(server.Kiosk))
+	router.HandleFunc("/event/{eventid}/kiosk/state", server.Handler(server.KioskState))
+	router.HandleFunc("/event/{eventid}/kiosk/control", server.Handler(server.KioskControl))
+	router.HandleFunc("/event/{eventid}/schedule", server.Handler(server.Schedule))
+	router.HandleFunc("/event/{eventid}/schedule/live", server.Handler(server.ScheduleLive))
 
 	router.HandleFunc("/event/{eventid}/team/create", server.Handler(server.CreateTeam))
 	router.HandleFunc("/event/{eventid}/team/{teamid}", server.Handler(server.Team))
 	router.HandleFunc("/event/{eventid}/team/{teamid}/edit", server.Handler(server.EditTeam))
 	router.HandleFunc("/event/{eventid}/team/{teamid}/delete", server.Handler(server.DeleteTeam))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/images", server.Handler(server.TeamImages))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/build", server.Handler(server.TeamBuild))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/report", server.Handler(server.ReportComment))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/feedback", server.Handler(server.RespondFeedback))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/card", server.Handler(server.TableCard))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/qr/{page}", server.Handler(server.TeamQR))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/membership", server.Handler(server.TeamMembership))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/notifications", server.Handler(server.TeamNotifications))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/invite/{token}", server.Handler(server.TeamInvite))
+	router.HandleFunc("/event/{eventid}/team/{teamid}/claim/{token}", server.Handler(server.ClaimMember))
 	router.HandleFunc("/event/{eventid}/vote/{teamid}", server.Handler(server.Vote))
+
+	router.HandleFunc("/play/{eventid}/{teamid}/{version}/{file...}", server.PlayBuild)
 }
 
 // Path returns a proper route for an event.
@@ -117,11 +160,28 @@ func (server *Server) Dashboard(context *Context) {
 		nonsubmitted := []*Team{}
 		for _, team := range teams {
 			if team.HasMember(context.CurrentUser) &&
-				!team.IsCompeting() {
+				!team.IsCompeting(context.Event.Submission) {
 				nonsubmitted = append(nonsubmitted, team)
 			}
 		}
 		context.Data["NotSubmittedTeams"] = nonsubmitted
+		context.Data["PendingInvites"] = PendingInvites(teams, context.CurrentUser)
+
+		statuses := linkStatusByTeam(context)
+		broken, yourBroken := []*Team{}, []*Team{}
+		for _, team := range teams {
+			if !statuses[team.ID].HasFailures() {
+				continue
+			}
+			broken = append(broken, team)
+			if team.HasMember(context.CurrentUser) {
+				yourBroken = append(yourBroken, team)
+			}
+		}
+		context.Data["BrokenLinkTeams"] = yourBroken
+		if context.CurrentUser.IsAdmin() {
+			context.Data["BrokenLinkCount"] = len(broken)
+		}
 	}
 
 	context.Render("event-dashboard")
@@ -0,0 +1,301 @@
+package event
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// SimulationOptions are alternative parameters for recomputing results.
+type SimulationOptions struct {
+	// Groups are voter groups with alternative weights.
+	Groups []VoterGroup
+	// Normalize rescales each voter's scores to the distribution of all scores.
+	Normalize bool
+	// MinVotes is the number of completed ballots a team needs to be placed.
+	MinVotes int
+	// Excluded are voters whose ballots are ignored.
+	Excluded []user.UserID
+}
+
+// DefaultSimulationOptions returns options matching the live configuration.
+func DefaultSimulationOptions(event *Event) SimulationOptions {
+	return SimulationOptions{
+		Groups: append([]VoterGroup{}, event.VoterGroups()...),
+	}
+}
+
+// IsExcluded checks whether ballots of userid are ignored.
+func (options *SimulationOptions) IsExcluded(userid user.UserID) bool {
+	return slices.Contains(options.Excluded, userid)
+}
+
+// SimulatedResult compares live and simulated results of a team.
+type SimulatedResult struct {
+	Live      *TeamResult
+	Simulated *TeamResult
+	// BelowMinimum is set when the team doesn't have enough votes to be placed.
+	BelowMinimum bool
+}
+
+// PlaceChange returns how many places the team moved up in the simulation.
+func (result *SimulatedResult) PlaceChange() int {
+	if result.Live.Place == 0 || result.Simulated.Place == 0 {
+		return 0
+	}
+	return result.Live.Place - result.Simulated.Place
+}
+
+// ScoreChange returns the difference of simulated and live overall score.
+func (result *SimulatedResult) ScoreChange() float64 {
+	return result.Simulated.Average.Overall.Score - result.Live.Average.Overall.Score
+}
+
+// Simulate recomputes results using alternative options.
+//
+// The results and event are not modified, results are ranked by the
+// live configuration and returned in simulated order.
+func Simulate(results []*TeamResult, event *Event, options SimulationOptions) []*SimulatedResult {
+	simulatedEvent := *event
+	simulatedEvent.Groups = options.Groups
+	simulatedEvent.Judges = nil
+	simulatedEvent.JudgePercentage = 0
+
+	live := make([]*TeamResult, 0, len(results))
+	for _, result := range results {
+		copied := *result
+		live = append(live, &copied)
+	}
+	RankResults(live, event)
+
+	counted := []*Ballot{}
+	for _, result := range live {
+		for _, ballot := range result.Ballots {
+			if ballot.Completed && !options.IsExcluded(ballot.Voter) {
+				counted = append(counted, ballot)
+			}
+		}
+	}
+	if options.Normalize {
+		counted = normalizeBallots(counted)
+	}
+
+	byteam := map[TeamID][]*Ballot{}
+	for _, ballot := range counted {
+		byteam[ballot.Team] = append(byteam[ballot.Team], ballot)
+	}
+
+	placed := []*TeamResult{}
+	unplaced := []*TeamResult{}
+	byid := map[TeamID]*SimulatedResult{}
+	for _, result := range live {
+		simulated := &TeamResult{
+			Team:          result.Team,
+			Ballots:       byteam[result.Team.ID],
+			MemberBallots: result.MemberBallots,
+		}
+		simulated.Complete = len(simulated.Ballots)
+		simulated.Pending = simulated.Complete
+		simulated.Average, simulated.GroupAverages = AverageScores(simulated.Ballots, &simulatedEvent)
+
+		compared := &SimulatedResult{
+			Live:         result,
+			Simulated:    simulated,
+			BelowMinimum: simulated.Complete < options.MinVotes,
+		}
+		byid[result.Team.ID] = compared
+
+		if compared.BelowMinimum {
+			unplaced = append(unplaced, simulated)
+		} else {
+			placed = append(placed, simulated)
+		}
+	}
+
+	RankResults(placed, &simulatedEvent)
+	RankResults(unplaced, &simulatedEvent)
+	for _, result := range unplaced {
+		result.Place = 0
+		result.SharedPlace = false
+	}
+
+	compared := []*SimulatedResult{}
+	for _, result := range append(placed, unplaced...) {
+		compared = append(compared, byid[result.Team.ID])
+	}
+	return compared
+}
+
+// normalizedAspects are the aspects rescaled by normalizeBallots.
+var normalizedAspects = []string{"Theme", "Enjoyment", "Aesthetics", "Innovation", "Bonus"}
+
+// normalizeBallots rescales every voter's scores to the mean and spread of all ballots.
+//
+// Voters with a single ballot are left as is, voters who gave
+// identical scores get the overall mean.
+func normalizeBallots(ballots []*Ballot) []*Ballot {
+	type stats struct{ mean, deviation float64 }
+	measure := func(ballots []*Ballot, aspect string) stats {
+		var s stats
+		for _, ballot := range ballots {
+			s.mean += ballot.Score(aspect)
+		}
+		s.mean /= float64(len(ballots))
+		for _, ballot := range ballots {
+			d := ballot.Score(aspect) - s.mean
+			s.deviation += d * d
+		}
+		s.deviation = math.Sqrt(s.deviation / float64(len(ballots)))
+		return s
+	}
+
+	byvoter := map[user.UserID][]*Ballot{}
+	for _, ballot := range ballots {
+		byvoter[ballot.Voter] = append(byvoter[ballot.Voter], ballot)
+	}
+
+	global := map[string]stats{}
+	for _, aspect := range normalizedAspects {
+		if len(ballots) > 0 {
+			global[aspect] = measure(ballots, aspect)
+		}
+	}
+
+	normalized := make([]*Ballot, 0, len(ballots))
+	for _, ballot := range ballots {
+		copied := *ballot
+		voterBallots := byvoter[ballot.Voter]
+		if len(voterBallots) >= 2 {
+			for _, aspect := range normalizedAspects {
+				voter := measure(voterBallots, aspect)
+				score := global[aspect].mean
+				if voter.deviation > 0 {
+					score += (ballot.Score(aspect) - voter.mean) / voter.deviation * global[aspect].deviation
+				}
+				copied.setScore(aspect, score)
+			}
+			copied.EnsureRange()
+			copied.UpdateTotal()
+		}
+		normalized = append(normalized, &copied)
+	}
+	return normalized
+}
+
+// setScore sets an aspect score based on a name.
+func (aspects *Aspects) setScore(name string, score float64) {
+	switch name {
+	case "Theme":
+		aspects.Theme.Score = score
+	case "Enjoyment":
+		aspects.Enjoyment.Score = score
+	case "Aesthetics":
+		aspects.Aesthetics.Score = score
+	case "Innovation":
+		aspects.Innovation.Score = score
+	case "Bonus":
+		aspects.Bonus.Score = score
+	case "Overall":
+		aspects.Overall.Score = score
+	}
+}
+
+// parseSimulationOptions reads simulation options from the request.
+func parseSimulationOptions(context *Context) (SimulationOptions, error) {
+	options := DefaultSimulationOptions(context.Event)
+	if context.FormValue("simulate") == "" {
+		return options, nil
+	}
+
+	groups := []VoterGroup{}
+	for i, group := range options.Groups {
+		weight, err := strconv.ParseFloat(context.FormValue(fmt.Sprintf("Group[%d].Weight", i)), 64)
+		if err != nil {
+			return options, fmt.Errorf("invalid weight for %q", group.Name)
+		}
+		group.Weight = weight
+		groups = append(groups, group)
+	}
+	if err := verifyGroups(groups); err != nil {
+		return options, err
+	}
+	options.Groups = groups
+
+	options.Normalize = context.FormValue("normalize") == "true"
+
+	if minvotes := context.FormValue("minvotes"); minvotes != "" {
+		var err error
+		options.MinVotes, err = strconv.Atoi(minvotes)
+		if err != nil || options.MinVotes < 0 {
+			return options, fmt.Errorf("invalid minimum votes %q", minvotes)
+		}
+	}
+
+	for _, value := range context.Request.Form["exclude"] {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return options, fmt.Errorf("invalid voter %q", value)
+		}
+		options.Excluded = append(options.Excluded, user.UserID(id))
+	}
+
+	return options, nil
+}
+
+// Simulation displays live results side by side with results under alternative parameters.
+func (server *Server) Simulation(context *Context) {
+	if !context.CurrentUser.IsAdmin() {
+		context.FlashError("Must be admin to simulate results.")
+		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return
+	}
+
+	options, err := parseSimulationOptions(context)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+		options = DefaultSimulationOptions(context.Event)
+	}
+
+	results, err := context.Events.Results(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+
+	voters := []*user.User{}
+	for _, u := range append(users, context.Event.PlaceholderUsers()...) {
+		for _, result := range results {
+			if result.HasReviewer(u.ID) {
+				voters = append(voters, u)
+				break
+			}
+		}
+	}
+	sort.Slice(voters, func(i, k int) bool {
+		return voters[i].Name < voters[k].Name
+	})
+
+	context.Data["Options"] = &options
+	context.Data["Voters"] = voters
+	context.Data["DefaultGroupWeight"] = 100 - totalWeight(options.Groups)
+	context.Data["Simulation"] = Simulate(results, context.Event, options)
+	context.Render("event-simulation")
+}
+
+// totalWeight returns the sum of group weights.
+func totalWeight(groups []VoterGroup) float64 {
+	total := 0.0
+	for _, group := range groups {
+		total += group.Weight
+	}
+	return total
+}
@@ -0,0 +1,208 @@
+package event
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// uniformBallot creates a completed ballot with all rated aspects set to score.
+func uniformBallot(voter user.UserID, team TeamID, score float64) *Ballot {
+	ballot := &Ballot{
+		Voter:     voter,
+		Team:      team,
+		Completed: true,
+		Aspects: Aspects{
+			Theme:      Aspect{Score: score},
+			Enjoyment:  Aspect{Score: score},
+			Aesthetics: Aspect{Score: score},
+			Innovation: Aspect{Score: score},
+		},
+	}
+	ballot.UpdateTotal()
+	return ballot
+}
+
+// simulationResults groups ballots into results of teams named "Team <id>".
+func simulationResults(event *Event, ballots []*Ballot) []*TeamResult {
+	results := []*TeamResult{}
+	byteam := map[TeamID]*TeamResult{}
+	for _, ballot := range ballots {
+		result, ok := byteam[ballot.Team]
+		if !ok {
+			result = rankedResult(fmt.Sprintf("Team %v", ballot.Team), 0)
+			result.Team.ID = ballot.Team
+			byteam[ballot.Team] = result
+			results = append(results, result)
+		}
+		result.Ballots = append(result.Ballots, ballot)
+		result.Complete++
+	}
+	for _, result := range results {
+		result.Average, result.GroupAverages = AverageScores(result.Ballots, event)
+	}
+	return results
+}
+
+func describeSimulated(compared []*SimulatedResult) []string {
+	got := []string{}
+	for _, result := range compared {
+		simulated := result.Simulated
+		switch {
+		case simulated.Place == 0:
+			got = append(got, simulated.Team.Name+"-")
+		case simulated.SharedPlace:
+			got = append(got, fmt.Sprintf("%v#%v=", simulated.Team.Name, simulated.Place))
+		default:
+			got = append(got, fmt.Sprintf("%v#%v", simulated.Team.Name, simulated.Place))
+		}
+	}
+	return got
+}
+
+func TestSimulate(t *testing.T) {
+	const judge = user.UserID(10)
+	event := &Event{Groups: []VoterGroup{{Name: "Judges", Weight: 20, Members: []user.UserID{judge}}}}
+
+	// jammers prefer team 1, the judge prefers team 2
+	ballots := []*Ballot{
+		uniformBallot(1, 1, 5), uniformBallot(2, 1, 4),
+		uniformBallot(1, 2, 4), uniformBallot(2, 2, 4),
+		uniformBallot(1, 3, 2), uniformBallot(2, 3, 3), uniformBallot(judge, 3, 3),
+		uniformBallot(judge, 1, 1),
+		uniformBallot(judge, 2, 5),
+		uniformBallot(1, 4, 5),
+	}
+
+	judgeWeight := func(weight float64) []VoterGroup {
+		return []VoterGroup{{Name: "Judges", Weight: weight, Members: []user.UserID{judge}}}
+	}
+
+	tests := []struct {
+		name    string
+		options SimulationOptions
+		expect  []string
+		changes []int
+	}{
+		{
+			name:    "live configuration",
+			options: DefaultSimulationOptions(event),
+			expect:  []string{"Team 4#1", "Team 2#2", "Team 1#3", "Team 3#4"},
+			changes: []int{0, 0, 0, 0},
+		},
+		{
+			name:    "judges weighted more",
+			options: SimulationOptions{Groups: judgeWeight(80)},
+			expect:  []string{"Team 4#1", "Team 2#2", "Team 3#3", "Team 1#4"},
+			changes: []int{0, 0, 1, -1},
+		},
+		{
+			name:    "excluded judge",
+			options: SimulationOptions{Groups: judgeWeight(80), Excluded: []user.UserID{judge}},
+			expect:  []string{"Team 4#1", "Team 1#2", "Team 2#3", "Team 3#4"},
+			changes: []int{0, 1, -1, 0},
+		},
+		{
+			name:    "minimum votes",
+			options: SimulationOptions{Groups: judgeWeight(20), MinVotes: 2},
+			expect:  []string{"Team 2#1", "Team 1#2", "Team 3#3", "Team 4-"},
+			changes: []int{1, 1, 1, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results := simulationResults(event, ballots)
+			compared := Simulate(results, event, test.options)
+
+			if got := describeSimulated(compared); !slices.Equal(got, test.expect) {
+				t.Errorf("got %v, expected %v", got, test.expect)
+			}
+			changes := []int{}
+			for _, result := range compared {
+				changes = append(changes, result.PlaceChange())
+			}
+			if !slices.Equal(changes, test.changes) {
+				t.Errorf("place changes: got %v, expected %v", changes, test.changes)
+			}
+
+			for _, result := range results {
+				if result.Place != 0 {
+					t.Errorf("%v: live result was modified", result.Team.Name)
+				}
+			}
+			if event.Groups[0].Weight != 20 {
+				t.Errorf("event was modified")
+			}
+		})
+	}
+}
+
+func TestSimulateNormalize(t *testing.T) {
+	event := &Event{}
+	// voter 1 is lenient, voter 2 is harsh, both prefer one of their games equally
+	ballots := []*Ballot{
+		uniformBallot(1, 1, 4), uniformBallot(1, 2, 5),
+		uniformBallot(2, 3, 3), uniformBallot(2, 4, 1),
+	}
+
+	live := Simulate(simulationResults(event, ballots), event, SimulationOptions{})
+	if got, expect := describeSimulated(live), []string{"Team 2#1", "Team 1#2", "Team 3#3", "Team 4#4"}; !slices.Equal(got, expect) {
+		t.Errorf("live: got %v, expected %v", got, expect)
+	}
+
+	normalized := Simulate(simulationResults(event, ballots), event, SimulationOptions{Normalize: true})
+	if got, expect := describeSimulated(normalized), []string{"Team 2#1=", "Team 3#1=", "Team 1#3=", "Team 4#3="}; !slices.Equal(got, expect) {
+		t.Errorf("normalized: got %v, expected %v", got, expect)
+	}
+}
+
+func TestNormalizeBallots(t *testing.T) {
+	ballots := []*Ballot{
+		uniformBallot(1, 1, 2), uniformBallot(1, 2, 3),
+		uniformBallot(2, 1, 4), uniformBallot(2, 2, 5),
+		uniformBallot(3, 1, 3), uniformBallot(3, 2, 3),
+		uniformBallot(4, 1, 1),
+	}
+	normalized := normalizeBallots(ballots)
+	if len(normalized) != len(ballots) {
+		t.Fatalf("got %v ballots", len(normalized))
+	}
+
+	// global mean and deviation of all theme scores
+	mean, deviation := meanStddev([]float64{2, 3, 4, 5, 3, 3, 1})
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	tests := []struct {
+		name  string
+		index int
+		theme float64
+	}{
+		{"harsh voter low", 0, mean - deviation},
+		{"harsh voter high", 1, mean + deviation},
+		{"lenient voter low", 2, mean - deviation},
+		{"lenient voter high", 3, mean + deviation},
+		{"identical scores", 4, mean},
+		{"single ballot", 6, 1},
+	}
+	for _, test := range tests {
+		if theme := normalized[test.index].Theme.Score; !near(theme, test.theme) {
+			t.Errorf("%v: got %v, expected %v", test.name, theme, test.theme)
+		}
+	}
+
+	for i, ballot := range normalized {
+		if !near(ballot.Overall.Score, ballot.Total()) {
+			t.Errorf("ballot %v: overall %v was not updated", i, ballot.Overall.Score)
+		}
+	}
+	if ballots[0].Theme.Score != 2 || normalized[0] == ballots[0] {
+		t.Error("input ballots were modified")
+	}
+	if len(normalizeBallots(nil)) != 0 {
+		t.Error("expected no ballots")
+	}
+}
@@ -2,7 +2,6 @@ package event
 
 import (
 	"errors"
-	"net/url"
 	"strconv"
 
 	"github.com/adinfinit/jamvote/internal/natsort"
@@ -45,6 +44,18 @@ type Team struct {
 	Name    string
 	Members []Member
 	Game    Game `datastore:",noindex"`
+
+	// Conflicts are voters with a conflict of interest, who cannot vote for the team.
+	Conflicts []user.UserID `datastore:",noindex"`
+
+	// Invites are pending invitations to join the team.
+	Invites []Invite `datastore:",noindex"`
+	// JoinRequests are pending requests by users to join the team.
+	JoinRequests []JoinRequest `datastore:",noindex"`
+	// Claims are links for binding unregistered members to accounts.
+	Claims []MemberClaim `datastore:",noindex"`
+	// Notifications are messages for team members, such as broken links.
+	Notifications []Notification `datastore:",noindex"`
 }
 
 // Member is a team member. There may not be a registered user.
@@ -60,11 +71,23 @@ type Game struct {
 
 	Noncompeting bool `datastore:",noindex"`
 
-	Link struct {
+	// Platforms are the platforms the game runs on.
+	Platforms []Platform `datastore:",noindex"`
+
+	// Links are typed links to the game, see GameLinks.
+	Links []Link `datastore:",noindex"`
+	// LegacyLink contains links from before typed links.
+	LegacyLink struct {
 		Jam      string `datastore:",noindex"`
 		Download string `datastore:",noindex"`
 		Facebook string `datastore:",noindex"`
-	} `datastore:",noindex"`
+	} `datastore:"Link,noindex"`
+
+	Cover       Image   `datastore:",noindex"`
+	Screenshots []Image `datastore:",noindex"`
+
+	// Build is the hosted HTML5 build.
+	Build Build `datastore:",noindex"`
 }
 
 // Less compares teams by name.
@@ -72,46 +95,23 @@ func (team *Team) Less(other *Team) bool {
 	return natsort.Less(team.Name, other.Name)
 }
 
-// Verify verifies whether team has valid state.
-func (team *Team) Verify() error {
+// Verify verifies whether team has valid state under the event rules.
+func (team *Team) Verify(rules TeamRules) error {
 	if team.Name == "" {
 		return errors.New("team name cannot be empty")
 	}
 	if len(team.Members) == 0 {
 		return errors.New("team must have at least one member")
 	}
-
-	if team.Game.Link.Jam != "" {
-		u, err := url.Parse(team.Game.Link.Jam)
-		if err != nil {
-			return errors.New("invalid Jam link: " + err.Error())
-		}
-		if u.Scheme != "http" && u.Scheme != "https" {
-			return errors.New("invalid Jam link")
-		}
-	}
-
-	if team.Game.Link.Download != "" {
-		u, err := url.Parse(team.Game.Link.Download)
-		if err != nil {
-			return errors.New("invalid Download link: " + err.Error())
-		}
-		if u.Scheme != "http" && u.Scheme != "https" {
-			return errors.New("invalid Download link")
-		}
+	if err := rules.checkMembers(len(team.Members)); err != nil {
+		return err
 	}
 
-	if team.Game.Link.Facebook != "" {
-		u, err := url.Parse(team.Game.Link.Facebook)
-		if err != nil {
-			return errors.New("invalid Facebook link: " + err.Error())
-		}
-		if u.Scheme != "http" && u.Scheme != "https" {
-			return errors.New("invalid Facebook link")
-		}
+	if err := verifyPlatforms(team.Game.Platforms); err != nil {
+		return err
 	}
 
-	return nil
+	return verifyLinks(team.Game.GameLinks())
 }
 
 // HasEditor checks whether user can edit the team.
@@ -143,22 +143,17 @@ func (team *Team) HasMemberID(userid user.UserID) bool {
 	return false
 }
 
-// HasSubmitted checks whether team has all information necessary.
-func (team *Team) HasSubmitted() bool {
+// HasSubmitted checks whether team has all information necessary under the event rules.
+func (team *Team) HasSubmitted(rules SubmissionRules) bool {
 	if team.Game.Name == "" {
 		return false
 	}
-
-	hasFacebook := team.Game.Link.Facebook != ""
-	hasJam := team.Game.Link.Jam != ""
-	hasDownload := team.Game.Link.Download != ""
-
-	return hasFacebook || hasJam || hasDownload
+	return rules.Submitted(&team.Game)
 }
 
 // IsCompeting returns whether team is part of the prizes.
-func (team *Team) IsCompeting() bool {
-	return team.HasSubmitted() && !team.Game.Noncompeting
+func (team *Team) IsCompeting(rules SubmissionRules) bool {
+	return team.HasSubmitted(rules) && !team.Game.Noncompeting
 }
 
 // MembersWithEmpty returns slice with additional empty members if needed.
@@ -170,14 +165,11 @@ func (team *Team) MembersWithEmpty() []Member {
 	return members
 }
 
-// MembersForEdit returns slice with additional empty members if needed.
-func (team *Team) MembersForEdit(isAdmin bool) []Member {
+// MembersForEdit returns slice with empty members up to the member limit.
+func (team *Team) MembersForEdit(rules TeamRules) []Member {
 	members := append([]Member{}, team.Members...)
 
-	maxMembers := SuggestedTeamMembers
-	if isAdmin {
-		maxMembers = MaxTeamMembers
-	}
+	maxMembers := rules.MemberLimit()
 	for len(members) < maxMembers {
 		members = append(members, Member{})
 	}
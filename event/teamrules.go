@@ -0,0 +1,150 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+// TeamRules are per-event limits on teams.
+type TeamRules struct {
+	// MinMembers is the minimum number of members, zero for no minimum.
+	MinMembers int
+	// MaxMembers is the maximum number of members, zero for MaxTeamMembers.
+	MaxMembers int
+	// MaxTeamsPerUser is the number of teams a user can be in, zero for unlimited.
+	MaxTeamsPerUser int
+	// DisallowSolo rejects teams with a single member.
+	DisallowSolo bool
+}
+
+// MemberLimit returns the maximum number of members.
+func (rules TeamRules) MemberLimit() int {
+	if rules.MaxMembers <= 0 || rules.MaxMembers > MaxTeamMembers {
+		return MaxTeamMembers
+	}
+	return rules.MaxMembers
+}
+
+// Verify checks that the rules are consistent.
+func (rules TeamRules) Verify() error {
+	if rules.MinMembers < 0 || rules.MaxMembers < 0 || rules.MaxTeamsPerUser < 0 {
+		return errors.New("team limits cannot be negative")
+	}
+	if rules.MaxMembers > MaxTeamMembers {
+		return fmt.Errorf("teams can have at most %v members", MaxTeamMembers)
+	}
+	if rules.MinMembers > rules.MemberLimit() {
+		return errors.New("minimum team size is larger than maximum")
+	}
+	if rules.DisallowSolo && rules.MemberLimit() < 2 {
+		return errors.New("solo entries cannot be disallowed when teams have at most 1 member")
+	}
+	return nil
+}
+
+// checkMembers checks the team size against the rules.
+func (rules TeamRules) checkMembers(count int) error {
+	if limit := rules.MemberLimit(); count > limit {
+		return fmt.Errorf("team can have at most %v members", limit)
+	}
+	if count < rules.MinMembers {
+		return fmt.Errorf("team must have at least %v members", rules.MinMembers)
+	}
+	if rules.DisallowSolo && count == 1 {
+		return errors.New("solo entries are not allowed, team must have at least 2 members")
+	}
+	return nil
+}
+
+// CheckTeamsPerUser checks that linked members of team are not in too many teams.
+func (rules TeamRules) CheckTeamsPerUser(team *Team, teams []*Team) error {
+	if rules.MaxTeamsPerUser <= 0 {
+		return nil
+	}
+	for _, member := range team.Members {
+		if member.ID == 0 {
+			continue
+		}
+		if err := rules.checkOtherTeams(member, team.ID, teams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CanJoin checks whether u can become a member of team.
+//
+// Only the teams of u are checked, existing members are not.
+func (rules TeamRules) CanJoin(team *Team, u *user.User, teams []*Team) error {
+	if rules.MaxTeamsPerUser <= 0 {
+		return nil
+	}
+	return rules.checkOtherTeams(Member{ID: u.ID, Name: u.Name}, team.ID, teams)
+}
+
+// checkOtherTeams checks whether member can be in one more team besides teams other than teamid.
+func (rules TeamRules) checkOtherTeams(member Member, teamid TeamID, teams []*Team) error {
+	count := 1
+	for _, other := range teams {
+		if other.ID != teamid && other.HasMemberID(member.ID) {
+			count++
+		}
+	}
+	if count > rules.MaxTeamsPerUser {
+		if rules.MaxTeamsPerUser == 1 {
+			return fmt.Errorf("%v is already in another team", member.Name)
+		}
+		return fmt.Errorf("%v can be in at most %v teams", member.Name, rules.MaxTeamsPerUser)
+	}
+	return nil
+}
+
+// checkTeamsPerUser verifies team against other teams of the event.
+func (server *Server) checkTeamsPerUser(context *Context, team *Team) error {
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		return fmt.Errorf("unable to get teams: %w", err)
+	}
+	return context.Event.TeamRules.CheckTeamsPerUser(team, teams)
+}
+
+// canJoinTeam checks whether u can join team under the event rules.
+func (server *Server) canJoinTeam(context *Context, team *Team, u *user.User) error {
+	teams, err := context.Events.Teams(context.Event.ID)
+	if err != nil {
+		return fmt.Errorf("unable to get teams: %w", err)
+	}
+	return context.Event.TeamRules.CanJoin(team, u, teams)
+}
+
+// parseTeamRules parses team rules from the event edit form.
+func parseTeamRules(context *Context) (TeamRules, error) {
+	number := func(name, label string) (int, error) {
+		value := context.FormValue(name)
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %v: %q", label, value)
+		}
+		return n, nil
+	}
+
+	var rules TeamRules
+	var err error
+	if rules.MinMembers, err = number("TeamRules.MinMembers", "minimum team size"); err != nil {
+		return rules, err
+	}
+	if rules.MaxMembers, err = number("TeamRules.MaxMembers", "maximum team size"); err != nil {
+		return rules, err
+	}
+	if rules.MaxTeamsPerUser, err = number("TeamRules.MaxTeamsPerUser", "teams per user"); err != nil {
+		return rules, err
+	}
+	rules.DisallowSolo = context.FormValue("TeamRules.DisallowSolo") == "true"
+	return rules, rules.Verify()
+}
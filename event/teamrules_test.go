@@ -0,0 +1,64 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/adinfinit/jamvote/user"
+)
+
+func TestTeamRules(t *testing.T) {
+	rules := TeamRules{MinMembers: 2, MaxMembers: 3, MaxTeamsPerUser: 1, DisallowSolo: true}
+	if err := rules.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	for count, valid := range map[int]bool{1: false, 2: true, 3: true, 4: false} {
+		if err := rules.checkMembers(count); (err == nil) != valid {
+			t.Errorf("%v members: got %v", count, err)
+		}
+	}
+
+	alice := &user.User{ID: 1, Name: "Alice"}
+	bob := &user.User{ID: 2, Name: "Bob"}
+	first := &Team{ID: 1, Members: []Member{{ID: alice.ID, Name: alice.Name}}}
+	second := &Team{ID: 2, Members: []Member{{Name: "Unregistered"}}}
+	teams := []*Team{first, second}
+
+	if err := rules.CanJoin(second, alice, teams); err == nil {
+		t.Error("alice should not be able to join a second team")
+	}
+	if err := rules.CanJoin(second, bob, teams); err != nil {
+		t.Error(err)
+	}
+	if err := rules.CheckTeamsPerUser(first, teams); err != nil {
+		t.Error(err)
+	}
+
+	invalid := []TeamRules{
+		{MinMembers: -1},
+		{MaxMembers: MaxTeamMembers + 1},
+		{MinMembers: 4, MaxMembers: 3},
+		{MaxMembers: 1, DisallowSolo: true},
+	}
+	for _, rules := range invalid {
+		if err := rules.Verify(); err == nil {
+			t.Errorf("%+v should be invalid", rules)
+		}
+	}
+}
+
+func TestMembersForEdit(t *testing.T) {
+	team := &Team{Members: []Member{{Name: "Alice"}}}
+	for _, test := range []struct {
+		rules TeamRules
+		count int
+	}{
+		{TeamRules{}, MaxTeamMembers},
+		{TeamRules{MaxMembers: 3}, 3},
+		{TeamRules{MaxMembers: 8}, 8},
+	} {
+		if count := len(team.MembersForEdit(test.rules)); count != test.count {
+			t.Errorf("%+v: got %v slots, want %v", test.rules, count, test.count)
+		}
+	}
+}
@@ -19,6 +19,19 @@ func findUserByName(users []*user.User, name string) (*user.User, bool) {
 	return nil, false
 }
 
+// previousMember finds the linked user of a member in previous by name.
+func previousMember(users []*user.User, previous *Team, name string) (*user.User, bool) {
+	if previous == nil {
+		return nil, false
+	}
+	for _, member := range previous.Members {
+		if member.ID != 0 && strings.EqualFold(member.Name, name) {
+			return findUserByID(users, member.ID)
+		}
+	}
+	return nil, false
+}
+
 // findUserByID finds a user by ID from users.
 func findUserByID(users []*user.User, id user.UserID) (*user.User, bool) {
 	for _, user := range users {
@@ -30,7 +43,11 @@ func findUserByID(users []*user.User, id user.UserID) (*user.User, bool) {
 }
 
 // parseTeamForm parses edited team page.
-func (server *Server) parseTeamForm(context *Context, users []*user.User) *Team {
+//
+// Members of previous keep their user, even when another user has the same name.
+// Other names are not linked to users, registered jammers join the team
+// with invites, join requests or by claiming their name.
+func (server *Server) parseTeamForm(context *Context, users []*user.User, previous *Team) *Team {
 	team := &Team{}
 	team.Name = context.FormValue("Team.Name")
 
@@ -44,7 +61,7 @@ func (server *Server) parseTeamForm(context *Context, users []*user.User) *Team
 
 	team.Members = nil
 	for _, memberName := range memberNames {
-		user, ok := findUserByName(users, memberName)
+		user, ok := previousMember(users, previous, memberName)
 		if !ok {
 			team.Members = append(team.Members, Member{
 				ID:   0,
@@ -63,13 +80,29 @@ func (server *Server) parseTeamForm(context *Context, users []*user.User) *Team
 	team.Game.Name = context.FormValue("Team.Game.Name")
 	team.Game.Info = context.FormValue("Team.Game.Info")
 	team.Game.Noncompeting = context.FormValue("Team.Game.Noncompeting") == "true"
-	team.Game.Link.Jam = context.FormValue("Team.Game.Link.Jam")
-	team.Game.Link.Download = context.FormValue("Team.Game.Link.Download")
-	team.Game.Link.Facebook = context.FormValue("Team.Game.Link.Facebook")
+	for _, platform := range context.Request.Form["Team.Game.Platforms"] {
+		team.Game.Platforms = append(team.Game.Platforms, Platform(platform))
+	}
+	team.Game.Links = parseLinks(context)
 
 	return team
 }
 
+// addCreator links creator to the member with the same name.
+//
+// Creators who aren't admins are added to the team when they aren't listed.
+func (team *Team) addCreator(creator *user.User) {
+	for i, member := range team.Members {
+		if member.ID == 0 && strings.EqualFold(member.Name, creator.Name) {
+			team.Members[i] = Member{ID: creator.ID, Name: creator.Name}
+			return
+		}
+	}
+	if !creator.IsAdmin() {
+		team.Members = append([]Member{{ID: creator.ID, Name: creator.Name}}, team.Members...)
+	}
+}
+
 // CreateTeam handles page for creating a new team.
 func (server *Server) CreateTeam(context *Context) {
 	if context.CurrentUser == nil {
@@ -89,7 +122,7 @@ func (server *Server) CreateTeam(context *Context) {
 	if err != nil {
 		context.FlashErrorNow(fmt.Sprintf("Unable to get list of users: %v", err))
 	}
-	context.Data["Users"] = users
+	context.Data["Platforms"] = Platforms
 	context.Data["Team"] = &Team{}
 
 	if context.Request.Method == http.MethodPost {
@@ -100,9 +133,16 @@ func (server *Server) CreateTeam(context *Context) {
 			return
 		}
 
-		team := server.parseTeamForm(context, users)
+		team := server.parseTeamForm(context, users, nil)
+		team.addCreator(context.CurrentUser)
 		context.Data["Team"] = team
-		if err := team.Verify(); err != nil {
+		if err := team.Verify(context.Event.TeamRules); err != nil {
+			context.FlashErrorNow(err.Error())
+			context.Response.WriteHeader(http.StatusBadRequest)
+			context.Render("event-team-create")
+			return
+		}
+		if err := server.checkTeamsPerUser(context, team); err != nil {
 			context.FlashErrorNow(err.Error())
 			context.Response.WriteHeader(http.StatusBadRequest)
 			context.Render("event-team-create")
@@ -135,7 +175,7 @@ func (server *Server) EditTeam(context *Context) {
 	if err != nil {
 		context.FlashErrorNow(fmt.Sprintf("Unable to get list of users: %v", err))
 	}
-	context.Data["Users"] = users
+	context.Data["Platforms"] = Platforms
 
 	if context.Request.Method == http.MethodPost {
 		if err := context.Request.ParseForm(); err != nil {
@@ -145,12 +185,37 @@ func (server *Server) EditTeam(context *Context) {
 			return
 		}
 
-		team := server.parseTeamForm(context, users)
+		team := server.parseTeamForm(context, users, context.Team)
 		team.EventID = context.Team.EventID
 		team.ID = context.Team.ID
+		team.Conflicts = context.Team.Conflicts
+		team.Invites = context.Team.Invites
+		team.JoinRequests = context.Team.JoinRequests
+		team.Claims = context.Team.Claims
+		team.Notifications = context.Team.Notifications
+		team.Game.Cover = context.Team.Game.Cover
+		team.Game.Screenshots = context.Team.Game.Screenshots
+		team.Game.Build = context.Team.Game.Build
 		context.Data["Team"] = team
 
-		if err := team.Verify(); err != nil {
+		if context.CurrentUser.IsAdmin() {
+			conflicts, err := parseConflicts(context.FormValue("Team.Conflicts"), users)
+			if err != nil {
+				context.FlashErrorNow(err.Error())
+				context.Response.WriteHeader(http.StatusBadRequest)
+				context.Render("event-team-edit")
+				return
+			}
+			team.Conflicts = conflicts
+		}
+
+		if err := team.Verify(context.Event.TeamRules); err != nil {
+			context.FlashErrorNow(err.Error())
+			context.Response.WriteHeader(http.StatusBadRequest)
+			context.Render("event-team-edit")
+			return
+		}
+		if err := server.checkTeamsPerUser(context, team); err != nil {
 			context.FlashErrorNow(err.Error())
 			context.Response.WriteHeader(http.StatusBadRequest)
 			context.Render("event-team-edit")
@@ -169,6 +234,8 @@ func (server *Server) EditTeam(context *Context) {
 		return
 	}
 
+	context.Data["ConflictNames"] = context.Team.conflictNames(users)
+
 	// Update names, if necessary
 	for i, member := range context.Team.Members {
 		if member.ID != 0 {
@@ -195,6 +262,17 @@ func (server *Server) DeleteTeam(context *Context) {
 		return
 	}
 
+	if server.Blobs != nil {
+		server.deleteImages(context, context.Team.Game.Cover)
+		server.deleteImages(context, context.Team.Game.Screenshots...)
+		if !context.Team.Game.Build.Empty() {
+			server.deleteBuild(context, context.Team, context.Team.Game.Build.Version)
+		}
+	}
+	if err := context.Events.DeleteLinkStatus(context.Event.ID, context.Team.ID); err != nil {
+		context.Site.Log.Error("failed to delete link status", "team", context.Team.ID, "error", err)
+	}
+
 	context.FlashMessage(fmt.Sprintf("Team %v deleted.", context.Team.ID))
 	context.Redirect(context.Event.Path("teams"), http.StatusSeeOther)
 }
@@ -214,6 +292,11 @@ func (server *Server) Team(context *Context) {
 			context.FlashError(err.Error())
 		}
 
+		reviews, err := server.teamCommentReviews(context, ballots)
+		if err != nil {
+			context.FlashError(err.Error())
+		}
+
 		var aspectsInfo AspectsInfo
 		for _, ballot := range ballots {
 			if !ballot.Completed {
@@ -222,12 +305,24 @@ func (server *Server) Team(context *Context) {
 			if context.CurrentUser != nil && ballot.Voter == context.CurrentUser.ID {
 				context.Data["CurrentUserBallot"] = ballot
 			}
-			aspectsInfo.Add(&ballot.Aspects, context.Team.HasMemberID(ballot.Voter))
+			aspects := ModeratedAspects(ballot, reviews)
+			aspectsInfo.Add(&aspects, ballot.Ref, context.Team.HasMemberID(ballot.Voter))
 		}
 		context.Data["Aspects"] = AspectDescriptionsWithOverall
 		context.Data["AspectsInfo"] = &aspectsInfo
+		feedbacks, err := context.Events.Feedbacks(context.Event.ID)
+		if err != nil {
+			context.FlashError(err.Error())
+		}
+		aspectsInfo.attachFeedback(feedbacks)
+
+		context.Data["CanReportComments"] = context.CurrentUser.IsAdmin() || context.Team.HasMember(context.CurrentUser)
+		context.Data["CanRespondComments"] = context.Team.HasMember(context.CurrentUser)
 	}
 
+	context.Data["LinkStatus"] = linkStatusByTeam(context)[context.Team.ID]
+
+	server.teamMembership(context)
 	context.Render("event-team")
 }
 
@@ -253,6 +348,16 @@ func (server *Server) Teams(context *Context) {
 	}
 	context.Data["MaxMemberCount"] = maxMembers
 
+	if context.CurrentUser.IsAdmin() {
+		legacy := 0
+		for _, team := range teams {
+			if team.Game.HasLegacyLinks() {
+				legacy++
+			}
+		}
+		context.Data["LegacyLinkTeams"] = legacy
+	}
+
 	if context.CurrentUser != nil {
 		yourteams := []*Team{}
 		for _, team := range teams {
@@ -263,6 +368,12 @@ func (server *Server) Teams(context *Context) {
 		context.Data["YourTeams"] = yourteams
 	}
 
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(fmt.Sprintf("Unable to get list of users: %v", err))
+	}
+	context.Data["Coverage"] = NewPlatformCoverage(teams, eventVoters(context.Event, users))
+
 	context.Render("event-teams")
 }
 
@@ -288,14 +399,9 @@ func (server *Server) Linking(context *Context) {
 		return teams[i].Less(teams[k])
 	})
 
-	type UserLink struct {
-		Member Member
-		User   *user.User
-	}
-
 	type Linking struct {
 		Team         *Team
-		Unlinked     []UserLink
+		Unlinked     []MemberLink
 		Unregistered []Member
 		Unapproved   []*user.User
 	}
@@ -314,10 +420,10 @@ func (server *Server) Linking(context *Context) {
 				continue
 			}
 
-			if match, ok := findUserByName(users, member.Name); ok {
-				link.Unlinked = append(link.Unlinked, UserLink{
-					Member: member,
-					User:   match,
+			if candidates := SuggestUsers(team, member, users); len(candidates) > 0 {
+				link.Unlinked = append(link.Unlinked, MemberLink{
+					Member:     member,
+					Candidates: candidates,
 				})
 			} else {
 				link.Unregistered = append(link.Unregistered, member)
@@ -330,6 +436,8 @@ func (server *Server) Linking(context *Context) {
 	}
 
 	context.Data["Linking"] = linking
+	context.Data["BulkLinkPercent"] = int(DefaultBulkLinkConfidence * 100)
+	context.Data["MinLinkPercent"] = int(MinLinkConfidence * 100)
 
 	context.Render("event-linking")
 }
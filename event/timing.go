@@ -0,0 +1,129 @@
+package event
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DurationBucket counts durations up to Max.
+type DurationBucket struct {
+	Label string
+	// Max is the exclusive upper bound, zero for the last bucket.
+	Max   time.Duration
+	Count int
+}
+
+// DurationDistribution is a histogram of durations.
+type DurationDistribution struct {
+	Name    string
+	Buckets []DurationBucket
+	Count   int
+	Median  time.Duration
+	// MaxCount is the largest bucket count, used for scaling.
+	MaxCount int
+}
+
+// durationBounds are the upper bounds of distribution buckets.
+var durationBounds = []time.Duration{
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	20 * time.Minute,
+	time.Hour,
+}
+
+// NewDurationDistribution creates a histogram of durations.
+func NewDurationDistribution(name string, durations []time.Duration) *DurationDistribution {
+	dist := &DurationDistribution{
+		Name:  name,
+		Count: len(durations),
+	}
+
+	previous := "0s"
+	for _, bound := range durationBounds {
+		label := formatDuration(bound)
+		dist.Buckets = append(dist.Buckets, DurationBucket{
+			Label: previous + " – " + label,
+			Max:   bound,
+		})
+		previous = label
+	}
+	dist.Buckets = append(dist.Buckets, DurationBucket{Label: "over " + previous})
+
+	for _, duration := range durations {
+		bucket := &dist.Buckets[len(dist.Buckets)-1]
+		for i := range dist.Buckets {
+			if duration < dist.Buckets[i].Max {
+				bucket = &dist.Buckets[i]
+				break
+			}
+		}
+		bucket.Count++
+		if bucket.Count > dist.MaxCount {
+			dist.MaxCount = bucket.Count
+		}
+	}
+
+	if len(durations) > 0 {
+		sorted := append([]time.Duration{}, durations...)
+		sort.Slice(sorted, func(i, k int) bool { return sorted[i] < sorted[k] })
+		middle := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			dist.Median = (sorted[middle-1] + sorted[middle]) / 2
+		} else {
+			dist.Median = sorted[middle]
+		}
+	}
+
+	return dist
+}
+
+// MedianText returns the median in a human readable form.
+func (dist *DurationDistribution) MedianText() string {
+	return formatDuration(dist.Median)
+}
+
+// BallotTimings returns distributions for waiting and voting times of ballots.
+func BallotTimings(ballots []*Ballot) (waiting, spent *DurationDistribution) {
+	waitingTimes := []time.Duration{}
+	spentTimes := []time.Duration{}
+	for _, ballot := range ballots {
+		if duration, ok := ballot.TimeWaiting(); ok {
+			waitingTimes = append(waitingTimes, duration)
+		}
+		if !ballot.Completed {
+			continue
+		}
+		if duration, ok := ballot.TimeSpent(); ok {
+			spentTimes = append(spentTimes, duration)
+		}
+	}
+	waiting = NewDurationDistribution("Time from assignment to opening", waitingTimes)
+	spent = NewDurationDistribution("Time from opening to submitting", spentTimes)
+	return waiting, spent
+}
+
+// formatDuration formats duration with a precision suitable for voting times.
+func formatDuration(duration time.Duration) string {
+	switch {
+	case duration < time.Minute:
+		return fmt.Sprintf("%ds", int(duration.Seconds()))
+	case duration < time.Hour:
+		minutes := int(duration.Minutes())
+		seconds := int(duration.Seconds()) % 60
+		if seconds == 0 {
+			return fmt.Sprintf("%dm", minutes)
+		}
+		return fmt.Sprintf("%dm%02ds", minutes, seconds)
+	default:
+		hours := int(duration.Hours())
+		minutes := int(duration.Minutes()) % 60
+		if minutes == 0 {
+			return fmt.Sprintf("%dh", hours)
+		}
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+}
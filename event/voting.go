@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"time"
 )
 
 // Range is an aspect range.
@@ -41,12 +42,22 @@ func (server *Server) FillQueue(context *Context) {
 		return
 	}
 
-	_, incomplete, err := context.Events.CreateIncompleteBallots(context.Event.ID, context.CurrentUser.ID)
+	voter := context.CurrentUser.ID
+	platforms := context.CurrentUser.Platforms
+	eligible := func(team *Team) bool {
+		return context.Event.CheckAssignable(voter, team) == nil && team.Game.PlayableOn(platforms)
+	}
+
+	_, incomplete, err := context.Events.CreateIncompleteBallots(context.Event.ID, voter, eligible)
 	if err != nil {
 		context.FlashError(err.Error())
 	}
 	if len(incomplete) == 0 {
-		context.FlashMessage("No more games available for now.")
+		if len(platforms) > 0 {
+			context.FlashMessage("No more games available for your platforms for now.")
+		} else {
+			context.FlashMessage("No more games available for now.")
+		}
 	}
 
 	context.Redirect(context.Event.Path("voting"), http.StatusSeeOther)
@@ -96,6 +107,14 @@ func (server *Server) Voting(context *Context) {
 		return completed[i].Overall.Score > completed[k].Overall.Score
 	})
 
+	if context.Event.Revealed {
+		feedback, err := server.voterFeedback(context, completed)
+		if err != nil {
+			context.FlashErrorNow(err.Error())
+		}
+		context.Data["Feedback"] = feedback
+	}
+
 	context.Data["Queue"] = queue
 	context.Data["Completed"] = completed
 
@@ -141,11 +160,30 @@ func (server *Server) Vote(context *Context) {
 	//}
 
 	ballot, err := context.Events.UserBallot(context.Event.ID, context.CurrentUser.ID, context.Team.ID)
-	if err != nil && err != ErrNotExists {
-		context.FlashErrorNow(err.Error())
+	if err != nil {
+		if err != ErrNotExists {
+			context.FlashError(fmt.Sprintf("Unable to get ballot: %v", err))
+			context.Redirect(context.Event.Path("voting"), http.StatusSeeOther)
+			return
+		}
+		ballot = nil
 	}
+	if err := context.Event.CheckVotable(context.CurrentUser.ID, context.Team, ballot != nil); err != nil {
+		context.FlashError(fmt.Sprintf("Cannot vote for %v: %v.", context.Team.Name, err))
+		context.Redirect(context.Event.Path("voting"), http.StatusSeeOther)
+		return
+	}
+
 	if ballot == nil {
 		ballot = &Ballot{}
+	} else if ballot.Opened.IsZero() && !ballot.Completed && !context.Event.Closed &&
+		context.Request.Method != http.MethodPost {
+		// only viewing the ballot counts as opening, so that a direct
+		// submission doesn't look like it took no time at all
+		ballot.Opened = time.Now().UTC()
+		if err := context.Events.SubmitBallot(context.Event.ID, ballot); err != nil {
+			context.FlashErrorNow(err.Error())
+		}
 	}
 
 	ballotinfo := &BallotInfo{
@@ -228,20 +266,18 @@ func (server *Server) Reveal(context *Context) {
 		context.FlashErrorNow(err.Error())
 	}
 
-	// remove noncompeting entries
+	// remove noncompeting and unscored entries
 	{
 		xs := results[:0]
 		for _, x := range results {
-			if x.Team.IsCompeting() {
+			if x.IsRanked(context.Event.Submission) {
 				xs = append(xs, x)
 			}
 		}
 		results = xs
 	}
 
-	sort.Slice(results, func(i, k int) bool {
-		return results[i].Average.Overall.Score > results[k].Average.Overall.Score
-	})
+	RankResults(results, context.Event)
 
 	if len(results) > 5 {
 		results = results[:5]
@@ -253,17 +289,26 @@ func (server *Server) Reveal(context *Context) {
 	context.Render("event-reveal")
 }
 
-// Results displays all the results.
-func (server *Server) Results(context *Context) {
+// canViewResults checks whether results are visible and redirects otherwise.
+func (server *Server) canViewResults(context *Context) bool {
 	if !context.Event.Voting {
 		context.FlashMessage("Voting has not yet started.")
 		context.Redirect(context.Event.Path(), http.StatusSeeOther)
-		return
+		return false
 	}
 
 	if !context.Event.Revealed {
 		context.FlashMessage("Voting results have not been yet revealed.")
 		context.Redirect(context.Event.Path(), http.StatusSeeOther)
+		return false
+	}
+
+	return true
+}
+
+// Results displays all the results.
+func (server *Server) Results(context *Context) {
+	if !server.canViewResults(context) {
 		return
 	}
 
@@ -272,15 +317,29 @@ func (server *Server) Results(context *Context) {
 		context.FlashErrorNow(err.Error())
 	}
 
-	sort.Slice(results, func(i, k int) bool {
-		a, b := results[i], results[k]
-		if a.Team.Game.Noncompeting != b.Team.Game.Noncompeting {
-			return !a.Team.Game.Noncompeting
+	RankResults(results, context.Event)
+	if context.CurrentUser.IsAdmin() {
+		for _, breaker := range context.Event.InvalidTieBreakers() {
+			context.FlashErrorNow(fmt.Sprintf("Tie-breaker %q is ignored, because it is no longer available.", breaker.Description()))
 		}
-		return a.Average.Overall.Score > b.Average.Overall.Score
-	})
+	}
+
+	feedbacks, err := context.Events.Feedbacks(context.Event.ID)
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	reviewers := HelpfulReviewers(feedbacks, users)
+	if len(reviewers) > 5 {
+		reviewers = reviewers[:5]
+	}
+	context.Data["HelpfulReviewers"] = reviewers
 
 	context.Data["Results"] = results
+	context.Data["LeaderboardAspects"] = LeaderboardAspects
 	context.Render("event-results")
 }
 
@@ -296,8 +355,8 @@ func (server *Server) Progress(context *Context) {
 	}
 
 	sort.Slice(results, func(i, k int) bool {
-		if results[i].HasSubmitted() != results[k].Team.HasSubmitted() {
-			return results[i].HasSubmitted()
+		if results[i].HasSubmitted(context.Event.Submission) != results[k].Team.HasSubmitted(context.Event.Submission) {
+			return results[i].HasSubmitted(context.Event.Submission)
 		}
 		return results[i].Team.Name < results[k].Team.Name
 	})
@@ -318,10 +377,28 @@ func (server *Server) Progress(context *Context) {
 		totalComplete += result.Complete
 	}
 
+	ballots := []*Ballot{}
+	for _, result := range results {
+		ballots = append(ballots, result.Ballots...)
+	}
+	context.Data["WaitingTimes"], context.Data["SpentTimes"] = BallotTimings(ballots)
+
 	context.Data["AveragePending"] = averagePending / float64(len(results))
 	context.Data["AverageComplete"] = averageComplete / float64(len(results))
 	context.Data["TotalComplete"] = totalComplete
 
+	teams := []*Team{}
+	for _, result := range results {
+		if result.HasSubmitted(context.Event.Submission) {
+			teams = append(teams, result.Team)
+		}
+	}
+	users, err := context.Users.List()
+	if err != nil {
+		context.FlashErrorNow(err.Error())
+	}
+	context.Data["Coverage"] = NewPlatformCoverage(teams, eventVoters(context.Event, users))
+
 	context.Data["VoteTarget"] = target
 	context.Data["VoteMax"] = max
 	context.Data["Progress"] = results
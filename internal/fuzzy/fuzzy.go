@@ -0,0 +1,138 @@
+// Package fuzzy implements approximate matching of person names.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// folding maps letters with diacritics to their base letter.
+var folding = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ä': 'a', 'ã': 'a', 'å': 'a', 'ā': 'a', 'ą': 'a',
+	'ç': 'c', 'č': 'c', 'ć': 'c',
+	'ď': 'd', 'đ': 'd',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e', 'ė': 'e', 'ę': 'e', 'ě': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i', 'ī': 'i', 'į': 'i',
+	'ķ': 'k',
+	'ļ': 'l', 'ł': 'l',
+	'ñ': 'n', 'ń': 'n', 'ņ': 'n', 'ň': 'n',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'ö': 'o', 'õ': 'o', 'ø': 'o', 'ō': 'o', 'ő': 'o',
+	'ř': 'r',
+	'š': 's', 'ś': 's', 'ş': 's',
+	'ť': 't', 'ţ': 't',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u', 'ū': 'u', 'ů': 'u', 'ű': 'u', 'ų': 'u',
+	'ý': 'y', 'ÿ': 'y',
+	'ž': 'z', 'ź': 'z', 'ż': 'z',
+}
+
+// Fold normalizes a name for comparison.
+//
+// It lowercases, removes diacritics and parenthesized remarks such as
+// "(artist)", and replaces punctuation and repeated whitespace with single spaces.
+func Fold(name string) string {
+	var b strings.Builder
+	depth := 0
+	space := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == '(' || r == '[':
+			depth++
+			continue
+		case (r == ')' || r == ']') && depth > 0:
+			depth--
+			continue
+		case depth > 0:
+			continue
+		}
+
+		if folded, ok := folding[r]; ok {
+			r = folded
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		} else {
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// Tokens returns the words of the folded name.
+func Tokens(name string) []string {
+	return strings.Fields(Fold(name))
+}
+
+// Distance returns the Levenshtein edit distance between a and b.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for k := range previous {
+		previous[k] = k
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for k := 1; k <= len(rb); k++ {
+			cost := 1
+			if ra[i-1] == rb[k-1] {
+				cost = 0
+			}
+			current[k] = min(previous[k]+1, current[k-1]+1, previous[k-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// ratio returns similarity of a and b based on edit distance, in range 0..1.
+func ratio(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Distance(a, b))/float64(longest)
+}
+
+// subsetScore is the similarity when all words of one name are in the other.
+const subsetScore = 0.9
+
+// Similarity returns how similar names a and b are, in range 0..1.
+//
+// It takes the best of comparing folded names, comparing names with sorted
+// words and checking whether all words of one name appear in the other.
+func Similarity(a, b string) float64 {
+	ta, tb := Tokens(a), Tokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	score := ratio(strings.Join(ta, " "), strings.Join(tb, " "))
+
+	slices.Sort(ta)
+	slices.Sort(tb)
+	score = max(score, ratio(strings.Join(ta, " "), strings.Join(tb, " ")))
+
+	short, long := ta, tb
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	if len(short) >= 2 && len(short) < len(long) && subset(short, long) {
+		score = max(score, subsetScore)
+	}
+	return score
+}
+
+// subset checks whether all of words are contained in other.
+func subset(words, other []string) bool {
+	for _, word := range words {
+		if !slices.Contains(other, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package fuzzy
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Jaan  Tamm", "jaan tamm"},
+		{"Jaan Tamm (artist)", "jaan tamm"},
+		{"Mari Mägi", "mari magi"},
+		{"Õie Sööt-Küüt", "oie soot kuut"},
+		{"  ", ""},
+	}
+	for _, test := range tests {
+		if got := Fold(test.name); got != test.want {
+			t.Errorf("Fold(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"mägi", "magi", 1},
+		{"abc", "", 3},
+	}
+	for _, test := range tests {
+		if got := Distance(test.a, test.b); got != test.want {
+			t.Errorf("Distance(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		min, max float64
+	}{
+		{"Jaan Tamm", "Jaan  Tamm", 1, 1},
+		{"Jaan Tamm", "jaan tamm (artist)", 1, 1},
+		{"Mari Mägi", "Mari Magi", 1, 1},
+		{"Tamm Jaan", "Jaan Tamm", 1, 1},
+		{"Jaan Tamm", "Jaan Tam", 0.85, 0.95},
+		{"Jaan Tamm", "Jaan Peeter Tamm", 0.9, 0.9},
+		{"Jaan Tamm", "Mari Mägi", 0, 0.5},
+		{"", "Jaan", 0, 0},
+	}
+	for _, test := range tests {
+		got := Similarity(test.a, test.b)
+		if got < test.min || got > test.max {
+			t.Errorf("Similarity(%q, %q) = %v, want %v..%v", test.a, test.b, got, test.min, test.max)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package imaging validates, resizes and encodes uploaded images.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"math"
+
+	// supported upload formats
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	// MaxPixels is the largest image that will be decoded.
+	MaxPixels = 40_000_000
+	// JPEGQuality is the quality of encoded images.
+	JPEGQuality = 85
+)
+
+// ErrFormat is returned for data that is not a supported image.
+var ErrFormat = errors.New("image must be a PNG, JPEG or GIF")
+
+// Decode decodes a PNG, JPEG or GIF image.
+//
+// The dimensions are checked before decoding, so that
+// huge images don't exhaust memory.
+func Decode(data []byte) (image.Image, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrFormat
+	}
+	switch format {
+	case "png", "jpeg", "gif":
+	default:
+		return nil, ErrFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrFormat
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("image can be at most %v megapixels", MaxPixels/1_000_000)
+	}
+
+	m, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	return m, nil
+}
+
+// Fit scales m down to fit within width and height, preserving the aspect ratio.
+//
+// Images that already fit are returned unchanged.
+func Fit(m image.Image, width, height int) image.Image {
+	size := m.Bounds().Size()
+	if size.X <= width && size.Y <= height {
+		return m
+	}
+	scale := min(float64(width)/float64(size.X), float64(height)/float64(size.Y))
+	return Resize(m,
+		max(1, int(math.Round(float64(size.X)*scale))),
+		max(1, int(math.Round(float64(size.Y)*scale))),
+	)
+}
+
+// Resize resamples m to width and height by averaging the covered source pixels.
+func Resize(m image.Image, width, height int) *image.RGBA {
+	src := toRGBA(m)
+	size := src.Bounds().Size()
+	columns := contributions(size.X, width)
+	rows := contributions(size.Y, height)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	row := make([]float64, size.X*4)
+	for y, sources := range rows {
+		clear(row)
+		for _, source := range sources {
+			line := src.Pix[source.index*src.Stride : source.index*src.Stride+size.X*4]
+			for i, v := range line {
+				row[i] += float64(v) * source.weight
+			}
+		}
+
+		out := dst.Pix[y*dst.Stride : y*dst.Stride+width*4]
+		for x, sources := range columns {
+			var r, g, b, a float64
+			for _, source := range sources {
+				p := row[source.index*4 : source.index*4+4]
+				r += p[0] * source.weight
+				g += p[1] * source.weight
+				b += p[2] * source.weight
+				a += p[3] * source.weight
+			}
+			out[x*4+0] = clamp(r)
+			out[x*4+1] = clamp(g)
+			out[x*4+2] = clamp(b)
+			out[x*4+3] = clamp(a)
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG encodes m as JPEG, transparent areas become white.
+func EncodeJPEG(m image.Image) ([]byte, error) {
+	bounds := m.Bounds()
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, m, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// contribution is the weight of a source pixel in a destination pixel.
+type contribution struct {
+	index  int
+	weight float64
+}
+
+// contributions calculates which source pixels cover each destination pixel.
+func contributions(src, dst int) [][]contribution {
+	scale := float64(src) / float64(dst)
+	result := make([][]contribution, dst)
+	for i := range result {
+		start, end := float64(i)*scale, float64(i+1)*scale
+		for k := int(start); k < src && float64(k) < end; k++ {
+			overlap := min(end, float64(k+1)) - max(start, float64(k))
+			if overlap > 0 {
+				result[i] = append(result[i], contribution{index: k, weight: overlap / scale})
+			}
+		}
+	}
+	return result
+}
+
+// toRGBA converts m to premultiplied RGBA with bounds starting at zero.
+func toRGBA(m image.Image) *image.RGBA {
+	if rgba, ok := m.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := m.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), m, bounds.Min, draw.Src)
+	return rgba
+}
+
+// clamp rounds v to a byte.
+func clamp(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint8(v + 0.5)
+}
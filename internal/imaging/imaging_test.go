@@ -0,0 +1,55 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	// left half black, right half white
+	src := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	for y := range 100 {
+		for x := 200; x < 400; x++ {
+			src.Set(x, y, color.White)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fitted := Fit(decoded, 100, 100)
+	if size := fitted.Bounds().Size(); size != image.Pt(100, 25) {
+		t.Fatalf("got size %v, expected 100x25", size)
+	}
+	for x, want := range map[int]uint8{0: 0, 49: 0, 50: 255, 99: 255} {
+		r, _, _, _ := fitted.At(x, 10).RGBA()
+		if uint8(r>>8) != want {
+			t.Errorf("pixel %v: got %v, expected %v", x, r>>8, want)
+		}
+	}
+
+	if small := Fit(decoded, 1000, 1000); small != decoded {
+		t.Error("images that fit should not be resized")
+	}
+
+	data, err := EncodeJPEG(fitted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decode(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Decode([]byte("<svg></svg>")); err != ErrFormat {
+		t.Errorf("got %v, expected ErrFormat", err)
+	}
+}
@@ -0,0 +1,530 @@
+// Package qr encodes short texts, such as links, as QR codes.
+//
+// Only byte mode with error correction level M is supported,
+// which is sufficient for links up to 213 bytes.
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// ErrTooLong is returned when the text does not fit into a supported QR code.
+var ErrTooLong = errors.New("qr: text is too long")
+
+// QuietZone is the number of light modules required around the code.
+const QuietZone = 4
+
+// Code is an encoded QR code.
+type Code struct {
+	// Size is the number of modules on each side, excluding the quiet zone.
+	Size    int
+	modules []bool
+}
+
+// Black returns whether the module at column x and row y is dark.
+//
+// Modules outside of the code are light.
+func (code *Code) Black(x, y int) bool {
+	if x < 0 || y < 0 || x >= code.Size || y >= code.Size {
+		return false
+	}
+	return code.modules[y*code.Size+x]
+}
+
+// SVG returns the code as a scalable SVG image including the quiet zone.
+func (code *Code) SVG() string {
+	var path strings.Builder
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Black(x, y) {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+QuietZone, y+QuietZone)
+			}
+		}
+	}
+
+	full := code.Size + 2*QuietZone
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %[1]d %[1]d" shape-rendering="crispEdges">`+
+		`<rect width="%[1]d" height="%[1]d" fill="#fff"/><path d="%[2]s" fill="#000"/></svg>`, full, path.String())
+}
+
+// version describes the codeword layout of a version at error correction level M.
+type version struct {
+	number int
+	// ecc is the number of error correction codewords per block.
+	ecc int
+	// blocks are the data codeword counts of each block.
+	blocks []int
+	// alignment are the alignment pattern center coordinates.
+	alignment []int
+}
+
+// dataCodewords returns the total number of data codewords.
+func (v *version) dataCodewords() int {
+	total := 0
+	for _, block := range v.blocks {
+		total += block
+	}
+	return total
+}
+
+// countBits returns the size of the character count field in byte mode.
+func (v *version) countBits() int {
+	if v.number < 10 {
+		return 8
+	}
+	return 16
+}
+
+var versions = []version{
+	{1, 10, []int{16}, nil},
+	{2, 16, []int{28}, []int{6, 18}},
+	{3, 26, []int{44}, []int{6, 22}},
+	{4, 18, []int{32, 32}, []int{6, 26}},
+	{5, 24, []int{43, 43}, []int{6, 30}},
+	{6, 16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{7, 18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{8, 22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{9, 22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{10, 26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+// formatLevelM are the error correction level bits in format information.
+const formatLevelM = 0
+
+// Encode encodes text as a QR code using the smallest version that fits.
+func Encode(text string) (*Code, error) {
+	for i := range versions {
+		v := &versions[i]
+		if 4+v.countBits()+8*len(text) <= 8*v.dataCodewords() {
+			return encode(v, []byte(text), -1), nil
+		}
+	}
+	return nil, ErrTooLong
+}
+
+// encode builds the code, mask -1 picks the mask with the lowest penalty.
+func encode(v *version, data []byte, mask int) *Code {
+	codewords := interleave(v, encodeData(v, data))
+
+	best := (*Code)(nil)
+	bestPenalty := 0
+	for m := 0; m < 8; m++ {
+		if mask >= 0 && m != mask {
+			continue
+		}
+		code := newMatrix(v)
+		code.placeData(codewords)
+		code.applyMask(m)
+		code.drawFormat(m)
+		if penalty := code.penalty(); best == nil || penalty < bestPenalty {
+			best, bestPenalty = code.Code(), penalty
+		}
+	}
+	return best
+}
+
+// encodeData creates data codewords in byte mode with padding.
+func encodeData(v *version, data []byte) []byte {
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), v.countBits())
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := 8 * v.dataCodewords()
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// interleave splits data into blocks, adds error correction and interleaves the result.
+func interleave(v *version, data []byte) []byte {
+	divisor := reedSolomonDivisor(v.ecc)
+
+	blocks := [][]byte{}
+	eccs := [][]byte{}
+	longest := 0
+	for _, size := range v.blocks {
+		block := data[:size]
+		data = data[size:]
+		blocks = append(blocks, block)
+		eccs = append(eccs, reedSolomonRemainder(block, divisor))
+		longest = max(longest, size)
+	}
+
+	result := []byte{}
+	for i := 0; i < longest; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecc; i++ {
+		for _, ecc := range eccs {
+			result = append(result, ecc[i])
+		}
+	}
+	return result
+}
+
+// bitBuffer is a sequence of bits.
+type bitBuffer []bool
+
+// append adds the lowest count bits of value, most significant first.
+func (bits *bitBuffer) append(value, count int) {
+	for i := count - 1; i >= 0; i-- {
+		*bits = append(*bits, (value>>i)&1 != 0)
+	}
+}
+
+// bytes packs the bits into bytes.
+func (bits bitBuffer) bytes() []byte {
+	result := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			result[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies x and y in GF(2^8) modulo 0x11D.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// reedSolomonDivisor returns the generator polynomial of degree, without the leading term.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// matrix is a code under construction.
+type matrix struct {
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// newMatrix creates a matrix with all function patterns drawn.
+func newMatrix(v *version) *matrix {
+	size := 17 + 4*v.number
+	m := &matrix{size: size}
+	m.modules = make([][]bool, size)
+	m.function = make([][]bool, size)
+	for y := range m.modules {
+		m.modules[y] = make([]bool, size)
+		m.function[y] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+
+	m.drawFinder(3, 3)
+	m.drawFinder(size-4, 3)
+	m.drawFinder(3, size-4)
+
+	last := len(v.alignment) - 1
+	for i, x := range v.alignment {
+		for k, y := range v.alignment {
+			if (i == 0 && k == 0) || (i == 0 && k == last) || (i == last && k == 0) {
+				continue
+			}
+			m.drawAlignment(x, y)
+		}
+	}
+
+	m.drawFormat(0)
+	m.drawVersion(v.number)
+	return m
+}
+
+// set sets a function module at column x and row y.
+func (m *matrix) set(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.function[y][x] = true
+}
+
+// drawFinder draws a finder pattern with separator centered at x, y.
+func (m *matrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= m.size || yy >= m.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			m.set(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern centered at x, y.
+func (m *matrix) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormat draws both copies of format information for mask.
+func (m *matrix) drawFormat(mask int) {
+	data := formatLevelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.set(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, m.size-15+i, bit(i))
+	}
+	m.set(8, m.size-8, true)
+}
+
+// drawVersion draws both copies of version information for versions 7 and up.
+func (m *matrix) drawVersion(number int) {
+	if number < 7 {
+		return
+	}
+	rem := number
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := number<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 != 0
+		a := m.size - 11 + i%3
+		b := i / 3
+		m.set(a, b, dark)
+		m.set(b, a, dark)
+	}
+}
+
+// placeData places codewords in the zigzag order.
+func (m *matrix) placeData(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				m.modules[y][x] = (codewords[i/8]>>(7-i%8))&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts data modules selected by mask.
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				m.modules[y][x] = !m.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the matrix for mask selection, lower is better.
+func (m *matrix) penalty() int {
+	result := 0
+	line := make([]bool, m.size)
+	for _, vertical := range []bool{false, true} {
+		for a := 0; a < m.size; a++ {
+			for b := 0; b < m.size; b++ {
+				if vertical {
+					line[b] = m.modules[b][a]
+				} else {
+					line[b] = m.modules[a][b]
+				}
+			}
+			result += linePenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.modules[y][x] {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size {
+				c := m.modules[y][x]
+				if c == m.modules[y][x+1] && c == m.modules[y+1][x] && c == m.modules[y+1][x+1] {
+					result += 3
+				}
+			}
+		}
+	}
+
+	total := m.size * m.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+	return result
+}
+
+// finderLike are module sequences resembling a finder pattern.
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// linePenalty scores runs of equal modules and finder-like patterns in a line.
+func linePenalty(line []bool) int {
+	result := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			result += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+11 <= len(line); i++ {
+		for _, pattern := range finderLike {
+			matches := true
+			for k, dark := range pattern {
+				if line[i+k] != dark {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				result += 40
+			}
+		}
+	}
+	return result
+}
+
+// Code converts the matrix into a Code.
+func (m *matrix) Code() *Code {
+	code := &Code{Size: m.size, modules: make([]bool, m.size*m.size)}
+	for y := 0; y < m.size; y++ {
+		copy(code.modules[y*m.size:], m.modules[y])
+	}
+	return code
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Image returns the code as an image with scale pixels per module, including the quiet zone.
+func (code *Code) Image(scale int) image.Image {
+	scale = max(scale, 1)
+	full := (code.Size + 2*QuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, full, full), color.Palette{color.White, color.Black})
+	for y := 0; y < full; y++ {
+		for x := 0; x < full; x++ {
+			if code.Black(x/scale-QuietZone, y/scale-QuietZone) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img
+}
+
+// PNG writes the code as a PNG image with scale pixels per module.
+func (code *Code) PNG(w io.Writer, scale int) error {
+	return png.Encode(w, code.Image(scale))
+}
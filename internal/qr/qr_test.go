@@ -0,0 +1,174 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" as 1-M from the QR code specification examples.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	got := reedSolomonRemainder(data, reedSolomonDivisor(10))
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	texts := []string{
+		"",
+		"https://jamvote.example/event/jam/vote/5629499534213120",
+		strings.Repeat("x", 120),
+		strings.Repeat("y", 213),
+	}
+	for _, text := range texts {
+		code, err := Encode(text)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(text), err)
+		}
+		if got := decode(t, code); got != text {
+			t.Errorf("round trip of %d bytes: got %q", len(text), got)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("z", 214)); err != ErrTooLong {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestImage(t *testing.T) {
+	code, err := Encode("https://jamvote.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const scale = 3
+	img := code.Image(scale)
+	full := (code.Size + 2*QuietZone) * scale
+	if img.Bounds().Dx() != full || img.Bounds().Dy() != full {
+		t.Fatalf("got bounds %v, want %d", img.Bounds(), full)
+	}
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			r, _, _, _ := img.At((x+QuietZone)*scale+1, (y+QuietZone)*scale+1).RGBA()
+			if black := r == 0; black != code.Black(x, y) {
+				t.Fatalf("module %d,%d does not match", x, y)
+			}
+		}
+	}
+}
+
+// decode reads the text back from code, verifying error correction.
+func decode(t *testing.T, code *Code) string {
+	t.Helper()
+
+	var v *version
+	for i := range versions {
+		if 17+4*versions[i].number == code.Size {
+			v = &versions[i]
+		}
+	}
+	if v == nil {
+		t.Fatalf("unknown size %d", code.Size)
+	}
+
+	m := newMatrix(v)
+	mask := -1
+	for candidate := 0; candidate < 8; candidate++ {
+		m.drawFormat(candidate)
+		matches := true
+		for y := 0; y < m.size; y++ {
+			for x := 0; x < m.size; x++ {
+				if m.function[y][x] && m.modules[y][x] != code.Black(x, y) {
+					matches = false
+				}
+			}
+		}
+		if matches {
+			mask = candidate
+		}
+	}
+	if mask < 0 {
+		t.Fatal("function patterns or format information do not match")
+	}
+
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if !m.function[y][x] {
+				m.modules[y][x] = code.Black(x, y)
+			}
+		}
+	}
+	m.applyMask(mask)
+
+	var bits bitBuffer
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if x := right - j; !m.function[y][x] {
+					bits = append(bits, m.modules[y][x])
+				}
+			}
+		}
+	}
+	codewords := bits.bytes()
+
+	blocks := make([][]byte, len(v.blocks))
+	offset := 0
+	for i := 0; i < v.blocks[len(v.blocks)-1]; i++ {
+		for b, size := range v.blocks {
+			if i < size {
+				blocks[b] = append(blocks[b], codewords[offset])
+				offset++
+			}
+		}
+	}
+	divisor := reedSolomonDivisor(v.ecc)
+	data := []byte{}
+	for b, block := range blocks {
+		ecc := []byte{}
+		for i := 0; i < v.ecc; i++ {
+			ecc = append(ecc, codewords[offset+i*len(blocks)+b])
+		}
+		if !bytes.Equal(reedSolomonRemainder(block, divisor), ecc) {
+			t.Fatalf("block %d error correction does not match", b)
+		}
+		data = append(data, block...)
+	}
+
+	var stream bitBuffer
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(count int) int {
+		value := 0
+		for _, bit := range stream[:count] {
+			value <<= 1
+			if bit {
+				value |= 1
+			}
+		}
+		stream = stream[count:]
+		return value
+	}
+	if mode := read(4); mode != 0b0100 {
+		t.Fatalf("unexpected mode %b", mode)
+	}
+	length := read(v.countBits())
+	text := []byte{}
+	for i := 0; i < length; i++ {
+		text = append(text, byte(read(8)))
+	}
+	return string(text)
+}
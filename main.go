@@ -17,6 +17,7 @@ import (
 
 	"github.com/adinfinit/jamvote/about"
 	"github.com/adinfinit/jamvote/auth"
+	"github.com/adinfinit/jamvote/blob"
 	"github.com/adinfinit/jamvote/datastoredb"
 	"github.com/adinfinit/jamvote/devdata"
 	"github.com/adinfinit/jamvote/event"
@@ -86,10 +87,22 @@ func main() {
 	}
 	abouts.Register(router)
 
+	blobs := newBlobStore(ctx, logger)
+	if blobs != nil {
+		blobServer := &blob.Server{
+			Log:    logger,
+			Store:  blobs,
+			Public: event.PublicBlob,
+		}
+		blobServer.Register(router)
+	}
+
 	events := &event.Server{
-		Site:  sites,
-		DB:    db,
-		Users: users,
+		Site:       sites,
+		DB:         db,
+		Blobs:      blobs,
+		Users:      users,
+		PlayDomain: os.Getenv("PLAY_DOMAIN"),
 	}
 	events.Register(router)
 
@@ -164,6 +177,27 @@ func getSecretData(logger *slog.Logger, name string) []byte {
 	return result.Payload.Data
 }
 
+// newBlobStore returns storage for uploads.
+// It uses the bucket BLOB_BUCKET when set, otherwise the directory
+// BLOB_DIR. Uploads are disabled when neither is set.
+func newBlobStore(ctx context.Context, logger *slog.Logger) blob.Store {
+	if name := os.Getenv("BLOB_BUCKET"); name != "" {
+		bucket, err := blob.NewBucket(ctx, name)
+		if err != nil {
+			logger.Error("failed to create bucket client", "bucket", name, "error", err)
+			os.Exit(1)
+		}
+		return bucket
+	}
+
+	if root := os.Getenv("BLOB_DIR"); root != "" {
+		return &blob.Dir{Root: root}
+	}
+
+	logger.Warn("BLOB_BUCKET and BLOB_DIR not set, uploads are disabled")
+	return nil
+}
+
 func newCookieSessionStore(logger *slog.Logger, secretString string) sessions.Store {
 	secret := []byte(secretString)
 	if len(secret) == 0 {
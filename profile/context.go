@@ -9,7 +9,8 @@ import (
 
 // Context is context for a user.
 type Context struct {
-	Teams event.TeamRepo
+	Teams  event.TeamRepo
+	Events event.Repo
 	*user.Context
 }
 
@@ -17,7 +18,8 @@ type Context struct {
 func (server *Server) Context(w http.ResponseWriter, r *http.Request) *Context {
 	context := &Context{}
 	context.Context = server.Users.Context(w, r)
-	context.Teams = server.Events.Events(context)
+	context.Events = server.Events.Events(context)
+	context.Teams = context.Events
 	return context
 }
 
@@ -1,6 +1,7 @@
 package profile
 
 import (
+	"fmt"
 	"net/http"
 	"path"
 
@@ -20,6 +21,7 @@ type Server struct {
 // Register registers user related endpoints.
 func (server *Server) Register(router *http.ServeMux) {
 	router.HandleFunc("/user/{userid}/edit", server.Handler(server.Edit))
+	router.HandleFunc("/user/{userid}/link-teams", server.Handler(server.LinkTeams))
 	router.HandleFunc("/user/{userid}", server.Handler(server.Profile))
 }
 
@@ -85,6 +87,13 @@ func (server *Server) Edit(context *Context) {
 		user.Facebook = context.FormValue("facebook")
 		user.Github = context.FormValue("github")
 
+		user.Platforms = nil
+		for _, platform := range context.Request.Form["platforms"] {
+			if event.Platform(platform).Valid() {
+				user.Platforms = append(user.Platforms, platform)
+			}
+		}
+
 		admin := context.FormValue("admin") == "true"
 		// only other admin can change admin status
 		if context.CurrentUser.IsAdmin() {
@@ -98,10 +107,62 @@ func (server *Server) Edit(context *Context) {
 			context.FlashMessage("User updated.")
 		}
 
+		// offer linking teams where the user was added by name before signing up
+		if err == nil && context.CurrentUser.ID == user.ID {
+			matches, err := event.FindMemberMatches(context.Events, user)
+			if err != nil {
+				context.Site.Log.Error("failed to find member matches", "error", err)
+			} else if len(matches) > 0 {
+				context.Redirect(path.Join("/user", user.ID.String(), "link-teams"), http.StatusSeeOther)
+				return
+			}
+		}
+
 		context.Redirect(path.Join("/user", user.ID.String()), http.StatusSeeOther)
 		return
 	}
 
 	context.Data["User"] = user
+	context.Data["Platforms"] = event.Platforms
 	context.Render("user-edit")
 }
+
+// LinkTeams lets a user confirm which teams listing their name are theirs.
+func (server *Server) LinkTeams(context *Context) {
+	userid, ok := getUserID(context)
+	if !ok {
+		context.Error("User ID not specified", http.StatusBadRequest)
+		return
+	}
+	if context.CurrentUser == nil || context.CurrentUser.ID != userid {
+		context.FlashError("You can only link teams to your own account.")
+		context.Redirect(path.Join("/user", userid.String()), http.StatusSeeOther)
+		return
+	}
+
+	if context.Request.Method == http.MethodPost {
+		if err := context.Request.ParseForm(); err != nil {
+			context.Error("Parse form: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		requested, err := event.RequestMemberMatches(context.Events, context.CurrentUser, context.Request.Form["match"])
+		if err != nil {
+			context.FlashError(err.Error())
+		}
+		if len(requested) > 0 {
+			context.FlashMessage(fmt.Sprintf("Requested to join %v teams, a team member or an organizer will confirm.", len(requested)))
+		}
+		context.Redirect(path.Join("/user", userid.String()), http.StatusSeeOther)
+		return
+	}
+
+	matches, err := event.FindMemberMatches(context.Events, context.CurrentUser)
+	if err != nil {
+		context.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	context.Data["Matches"] = matches
+	context.Render("user-link-teams")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/gob"
 	"errors"
+	"slices"
 	"strconv"
 
 	"github.com/adinfinit/jamvote/auth"
@@ -46,6 +47,9 @@ type User struct {
 	Facebook string `datastore:",noindex"`
 	Github   string `datastore:",noindex"`
 
+	// Platforms are the platforms the user can play games on.
+	Platforms []string `datastore:",noindex"`
+
 	NewUser bool `datastore:"-"`
 }
 
@@ -66,6 +70,11 @@ func (user *User) HasEditor(editor *User) bool {
 	return editor != nil && user.ID == editor.ID
 }
 
+// HasPlatform returns whether user can play games on platform.
+func (user *User) HasPlatform(platform string) bool {
+	return slices.Contains(user.Platforms, platform)
+}
+
 // Equals returns whether b represent the same entity.
 func (user *User) Equals(b *User) bool {
 	return user.ID == b.ID